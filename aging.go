@@ -0,0 +1,94 @@
+package main
+
+import "fmt"
+
+// agingPolicy is a priority policy whose waiting tasks become more urgent the
+// longer they sit in the ready queue, so that a steady stream of urgent work
+// cannot starve the rest.
+type agingPolicy struct {
+	*orderedPolicy
+	interval  int64
+	step      int64
+	highFirst bool
+	agedAt    map[*Task]int64 // when each waiting task last aged, or entered the queue
+}
+
+// newPriorityPolicy returns the policy behind the priority schedulers, with
+// aging turned on when the AgingInterval option is set.
+func newPriorityPolicy(opts Options, preemptive bool) Policy {
+	ordered := newOrderedPolicy(morePriority(opts), 0, preemptive)
+	if opts.AgingInterval <= 0 {
+		return ordered
+	}
+
+	return &agingPolicy{
+		orderedPolicy: ordered,
+		interval:      opts.AgingInterval,
+		step:          opts.AgingStep,
+		highFirst:     opts.HighPriorityFirst,
+		agedAt:        make(map[*Task]int64),
+	}
+}
+
+func (p *agingPolicy) Ready(now int64, t *Task) {
+	p.agedAt[t] = now
+	p.orderedPolicy.Ready(now, t)
+}
+
+func (p *agingPolicy) Next(now int64) *Task {
+	t := p.orderedPolicy.Next(now)
+	delete(p.agedAt, t)
+	return t
+}
+
+func (p *agingPolicy) Steal(now int64, allowed func(*Task) bool) *Task {
+	t := p.orderedPolicy.Steal(now, allowed)
+	delete(p.agedAt, t)
+	return t
+}
+
+func (p *agingPolicy) Wake(int64) int64 {
+	wake := int64(-1)
+	for _, at := range p.agedAt {
+		if next := at + p.interval; wake < 0 || next < wake {
+			wake = next
+		}
+	}
+	return wake
+}
+
+func (p *agingPolicy) Timer(now int64) {
+	for t, at := range p.agedAt {
+		if now-at < p.interval {
+			continue
+		}
+		if p.highFirst {
+			t.Effective += p.step
+		} else {
+			t.Effective -= p.step
+		}
+		t.PriorityHistory = append(t.PriorityHistory, PriorityChange{At: now, Priority: t.Effective})
+		p.agedAt[t] = at + p.interval
+	}
+	p.queue.fix()
+}
+
+// agedResult adds the final effective priority of each process to the
+// schedule table when the run used aging.
+func agedResult(opts Options, r Result) Result {
+	if opts.AgingInterval <= 0 {
+		return r
+	}
+
+	values := make([]string, len(r.Processes))
+	for i, p := range r.Processes {
+		effective := p.Priority
+		if n := len(p.PriorityHistory); n > 0 {
+			effective = p.PriorityHistory[n-1].Priority
+		}
+		values[i] = fmt.Sprint(effective)
+	}
+	r.Columns = append(r.Columns, Column{Header: "Effective", Values: values})
+
+	return r
+}
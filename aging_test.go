@@ -0,0 +1,63 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPriorityAging(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3, Priority: 5},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 4, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 4, BurstDuration: 4, Priority: 1},
+		{ProcessID: 4, ArrivalTime: 8, BurstDuration: 4, Priority: 1},
+	}
+	tests := []struct {
+		name          string
+		agingInterval int64
+		wantGantt     []TimeSlice
+		wantHistory   []PriorityChange
+		wantColumns   []Column
+	}{
+		{
+			name: "no aging starves the low priority process",
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 4},
+				{PID: 3, Start: 4, Stop: 8},
+				{PID: 4, Start: 8, Stop: 12},
+				{PID: 1, Start: 12, Stop: 15},
+			},
+		},
+		{
+			name:          "aging lets it catch up",
+			agingInterval: 2,
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 4},
+				{PID: 3, Start: 4, Stop: 8},
+				{PID: 1, Start: 8, Stop: 11},
+				{PID: 4, Start: 11, Stop: 15},
+			},
+			wantHistory: []PriorityChange{{At: 2, Priority: 4}, {At: 4, Priority: 3}, {At: 6, Priority: 2}, {At: 8, Priority: 1}},
+			wantColumns: []Column{{Header: "Effective", Values: []string{"1", "1", "1", "0"}}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultOptions()
+			opts.AgingInterval = tt.agingInterval
+			got := priority(opts, processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(got.Processes[0].PriorityHistory, tt.wantHistory) {
+				t.Errorf("PriorityHistory = %v, want %v", got.Processes[0].PriorityHistory, tt.wantHistory)
+			}
+			if !reflect.DeepEqual(got.Columns, tt.wantColumns) {
+				t.Errorf("Columns = %v, want %v", got.Columns, tt.wantColumns)
+			}
+		})
+	}
+}
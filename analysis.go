@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// ErrAnalysisMismatch is returned when a simulation contradicts the
+// schedulability analysis of the same task set.
+var ErrAnalysisMismatch = errors.New("simulation disagrees with analysis")
+
+// Analysis is the schedulability of a set of periodic tasks, worked out
+// without simulating them.
+type Analysis struct {
+	Tasks []TaskAnalysis
+	// Utilization is the total share of the CPU the tasks need.
+	Utilization float64
+	// LiuLayland is the rate monotonic utilization bound n(2^(1/n) - 1), and
+	// Hyperbolic is the product of (U_i + 1), which must not exceed 2.
+	// Both are sufficient tests that only apply when deadlines equal periods.
+	LiuLayland float64
+	Hyperbolic float64
+	// Implicit is set when every deadline equals its period.
+	Implicit bool
+	Verdicts []Verdict
+}
+
+// TaskAnalysis is the worst-case response time of one task under each
+// fixed-priority scheduler, from exact response-time analysis.
+type TaskAnalysis struct {
+	PeriodicTask
+	RMResponse int64
+	DMResponse int64
+}
+
+// Verdict is the analytical answer for one scheduler. It is only Conclusive
+// when the test used is certain, e.g. a sufficient test that passed.
+type Verdict struct {
+	Scheduler   string
+	Test        string
+	Schedulable bool
+	Conclusive  bool
+}
+
+// Analyze works out whether tasks can be scheduled by rm, dm and edf.
+func Analyze(tasks []PeriodicTask) Analysis {
+	a := Analysis{Tasks: make([]TaskAnalysis, len(tasks)), Hyperbolic: 1, Implicit: true}
+	var density float64
+	loose, bounded, synchronous := true, true, true
+	for i, t := range tasks {
+		u := float64(t.WCET) / float64(t.Period)
+		a.Utilization += u
+		a.Hyperbolic *= u + 1
+		if d := t.RelativeDeadline(); d < t.Period {
+			density += float64(t.WCET) / float64(d)
+			loose = false
+		} else {
+			density += u
+			bounded = bounded && d == t.Period
+		}
+		synchronous = synchronous && t.Phase == 0
+		a.Implicit = a.Implicit && t.RelativeDeadline() == t.Period
+		a.Tasks[i].PeriodicTask = t
+	}
+	if n := float64(len(tasks)); n > 0 {
+		a.LiuLayland = n * (math.Pow(2, 1/n) - 1)
+	}
+
+	// Response-time analysis looks at the first job of each task, released
+	// all at once. That is the worst case, so a pass is always safe, but a
+	// failure is only certain when the tasks have no phases. Deadlines past the
+	// period need more than one job to be looked at, so nothing is certain.
+	rmOK := responseTimes(a.Tasks, func(t PeriodicTask) int64 { return t.Period }, func(ta *TaskAnalysis) *int64 { return &ta.RMResponse })
+	dmOK := responseTimes(a.Tasks, PeriodicTask.RelativeDeadline, func(ta *TaskAnalysis) *int64 { return &ta.DMResponse })
+	a.Verdicts = []Verdict{
+		{Scheduler: "rm", Test: "response time", Schedulable: rmOK, Conclusive: bounded && (rmOK || synchronous)},
+		{Scheduler: "dm", Test: "response time", Schedulable: dmOK, Conclusive: bounded && (dmOK || synchronous)},
+	}
+
+	// Under EDF a utilization of at most 1 is exact when no deadline is
+	// shorter than its period. Otherwise the density test is only sufficient.
+	// An overload always misses a deadline eventually, but the simulation of
+	// one hyperperiod only shows it when every job is due within the
+	// hyperperiod: every task released at 0 with no deadline past its period.
+	switch {
+	case a.Utilization > 1:
+		a.Verdicts = append(a.Verdicts, Verdict{Scheduler: "edf", Test: "utilization", Conclusive: bounded && synchronous})
+	case loose:
+		a.Verdicts = append(a.Verdicts, Verdict{Scheduler: "edf", Test: "utilization", Schedulable: true, Conclusive: true})
+	default:
+		a.Verdicts = append(a.Verdicts, Verdict{Scheduler: "edf", Test: "density", Schedulable: density <= 1, Conclusive: density <= 1})
+	}
+
+	return a
+}
+
+// responseTimes fills in each task's worst-case response time when tasks with
+// a smaller key have higher priority, and reports whether every task meets its
+// deadline. Tasks with equal keys keep their order, as the simulator does.
+func responseTimes(tasks []TaskAnalysis, key func(PeriodicTask) int64, response func(*TaskAnalysis) *int64) bool {
+	order := make([]int, len(tasks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return key(tasks[order[i]].PeriodicTask) < key(tasks[order[j]].PeriodicTask) })
+
+	ok := true
+	for k, i := range order {
+		t := tasks[i]
+		r := t.WCET
+		for {
+			next := t.WCET
+			for _, j := range order[:k] {
+				hp := tasks[j]
+				next += (r + hp.Period - 1) / hp.Period * hp.WCET
+			}
+			if next == r || next > t.RelativeDeadline() {
+				r = next
+				break
+			}
+			r = next
+		}
+		*response(&tasks[i]) = r
+		ok = ok && r <= t.RelativeDeadline()
+	}
+
+	return ok
+}
+
+// checkAnalysis simulates the jobs of tasks under each scheduler the analysis
+// gave a verdict for, and returns ErrAnalysisMismatch if any simulation
+// contradicts a conclusive verdict. It returns whether each simulation met
+// every deadline, in the order of a.Verdicts.
+func checkAnalysis(opts Options, tasks []PeriodicTask, a Analysis) ([]bool, error) {
+	// The analysis assumes one CPU, on which switching between tasks is free.
+	opts.ContextSwitch = 0
+	opts.CPUs = 1
+	opts.RunQueue = RunQueueGlobal
+	opts.Balance = BalanceOptions{}
+	jobs := expandJobs(tasks)
+
+	met := make([]bool, len(a.Verdicts))
+	var mismatched []string
+	for i, v := range a.Verdicts {
+		reg, err := Lookup(v.Scheduler)
+		if err != nil {
+			return nil, err
+		}
+		met[i] = reg.New(opts).Run(jobs).DeadlineMisses == 0
+		if v.Conclusive && met[i] != v.Schedulable {
+			mismatched = append(mismatched, v.Scheduler)
+		}
+	}
+	if len(mismatched) > 0 {
+		return met, fmt.Errorf("%w: %s", ErrAnalysisMismatch, strings.Join(mismatched, ", "))
+	}
+
+	return met, nil
+}
+
+// RenderAnalysis writes the response times and verdicts of an analysis, along
+// with whether each simulation met every deadline.
+func RenderAnalysis(w io.Writer, a Analysis, met []bool) {
+	outputTitle(w, "Schedulability analysis")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Period", "WCET", "Deadline", "Phase", "Utilization", "RM response", "DM response"})
+	for _, t := range a.Tasks {
+		table.Append([]string{
+			fmt.Sprint(t.TaskID),
+			fmt.Sprint(t.Period),
+			fmt.Sprint(t.WCET),
+			fmt.Sprint(t.RelativeDeadline()),
+			fmt.Sprint(t.Phase),
+			fmt.Sprintf("%.3f", float64(t.WCET)/float64(t.Period)),
+			fmt.Sprint(t.RMResponse),
+			fmt.Sprint(t.DMResponse),
+		})
+	}
+	table.Render()
+
+	_, _ = fmt.Fprintf(w, "Total utilization: %.3f\n", a.Utilization)
+	_, _ = fmt.Fprintf(w, "Liu & Layland bound: %.3f (%s)\n", a.LiuLayland, a.passes(a.Utilization <= a.LiuLayland))
+	_, _ = fmt.Fprintf(w, "Hyperbolic bound: %.3f <= 2 (%s)\n", a.Hyperbolic, a.passes(a.Hyperbolic <= 2))
+
+	table = tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Scheduler", "Test", "Analysis", "Simulation", "Agrees?"})
+	for i, v := range a.Verdicts {
+		analysis, agrees := "unknown", "-"
+		if v.Conclusive {
+			analysis, agrees = yesNo(v.Schedulable), yesNo(v.Schedulable == met[i])
+		}
+		table.Append([]string{v.Scheduler, v.Test, analysis, yesNo(met[i]), agrees})
+	}
+	table.Render()
+}
+
+// passes describes the outcome of one of the utilization bounds, which only
+// apply when every deadline equals its period.
+func (a Analysis) passes(ok bool) string {
+	switch {
+	case !a.Implicit:
+		return "does not apply"
+	case ok:
+		return "passes"
+	}
+	return "inconclusive"
+}
+
+func yesNo(ok bool) string {
+	if ok {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		tasks        []PeriodicTask
+		wantRM       []int64
+		wantDM       []int64
+		wantVerdicts []Verdict
+	}{
+		{
+			name: "within the Liu & Layland bound",
+			tasks: []PeriodicTask{
+				{TaskID: 1, Period: 4, WCET: 1},
+				{TaskID: 2, Period: 6, WCET: 2},
+				{TaskID: 3, Period: 12, WCET: 3},
+			},
+			wantRM: []int64{1, 3, 10},
+			wantDM: []int64{1, 3, 10},
+			wantVerdicts: []Verdict{
+				{Scheduler: "rm", Test: "response time", Schedulable: true, Conclusive: true},
+				{Scheduler: "dm", Test: "response time", Schedulable: true, Conclusive: true},
+				{Scheduler: "edf", Test: "utilization", Schedulable: true, Conclusive: true},
+			},
+		},
+		{
+			name: "full utilization",
+			tasks: []PeriodicTask{
+				{TaskID: 1, Period: 4, WCET: 2},
+				{TaskID: 2, Period: 6, WCET: 3},
+			},
+			wantRM: []int64{2, 7},
+			wantDM: []int64{2, 7},
+			wantVerdicts: []Verdict{
+				{Scheduler: "rm", Test: "response time", Conclusive: true},
+				{Scheduler: "dm", Test: "response time", Conclusive: true},
+				{Scheduler: "edf", Test: "utilization", Schedulable: true, Conclusive: true},
+			},
+		},
+		{
+			name: "constrained deadline",
+			tasks: []PeriodicTask{
+				{TaskID: 1, Period: 5, WCET: 2},
+				{TaskID: 2, Period: 10, WCET: 3, Deadline: 3},
+			},
+			wantRM: []int64{2, 5},
+			wantDM: []int64{5, 3},
+			wantVerdicts: []Verdict{
+				{Scheduler: "rm", Test: "response time", Conclusive: true},
+				{Scheduler: "dm", Test: "response time", Schedulable: true, Conclusive: true},
+				{Scheduler: "edf", Test: "density"},
+			},
+		},
+		{
+			name: "overloaded",
+			tasks: []PeriodicTask{
+				{TaskID: 1, Period: 2, WCET: 1},
+				{TaskID: 2, Period: 3, WCET: 2},
+			},
+			wantRM: []int64{1, 4},
+			wantDM: []int64{1, 4},
+			wantVerdicts: []Verdict{
+				{Scheduler: "rm", Test: "response time", Conclusive: true},
+				{Scheduler: "dm", Test: "response time", Conclusive: true},
+				{Scheduler: "edf", Test: "utilization", Conclusive: true},
+			},
+		},
+		{
+			name: "overloaded with a deadline past the period",
+			tasks: []PeriodicTask{
+				{TaskID: 1, Period: 10, WCET: 11, Deadline: 100},
+			},
+			wantRM: []int64{11},
+			wantDM: []int64{11},
+			wantVerdicts: []Verdict{
+				{Scheduler: "rm", Test: "response time", Schedulable: true},
+				{Scheduler: "dm", Test: "response time", Schedulable: true},
+				{Scheduler: "edf", Test: "utilization"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := Analyze(tt.tasks)
+			for i, ta := range a.Tasks {
+				if ta.RMResponse != tt.wantRM[i] || ta.DMResponse != tt.wantDM[i] {
+					t.Errorf("task %d response = %d, %d, want %d, %d", ta.TaskID, ta.RMResponse, ta.DMResponse, tt.wantRM[i], tt.wantDM[i])
+				}
+			}
+			if !reflect.DeepEqual(a.Verdicts, tt.wantVerdicts) {
+				t.Errorf("Verdicts = %v, want %v", a.Verdicts, tt.wantVerdicts)
+			}
+			if _, err := checkAnalysis(DefaultOptions(), tt.tasks, a); err != nil {
+				t.Errorf("checkAnalysis() error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_checkAnalysisCPUs(t *testing.T) {
+	t.Parallel()
+	tasks := []PeriodicTask{
+		{TaskID: 1, Period: 4, WCET: 2},
+		{TaskID: 2, Period: 6, WCET: 3},
+	}
+	opts := DefaultOptions()
+	opts.CPUs = 2
+	opts.RunQueue = RunQueuePerCPU
+	opts.Balance.Interval = 1
+	met, err := checkAnalysis(opts, tasks, Analyze(tasks))
+	if err != nil {
+		t.Errorf("checkAnalysis() error = %v", err)
+	}
+	if want := []bool{false, false, true}; !reflect.DeepEqual(met, want) {
+		t.Errorf("checkAnalysis() = %v, want %v", met, want)
+	}
+}
+
+func TestAnalyzeBounds(t *testing.T) {
+	t.Parallel()
+	a := Analyze([]PeriodicTask{
+		{TaskID: 1, Period: 4, WCET: 1},
+		{TaskID: 2, Period: 8, WCET: 2},
+	})
+	if math.Abs(a.Utilization-0.5) > 1e-9 {
+		t.Errorf("Utilization = %v, want 0.5", a.Utilization)
+	}
+	if math.Abs(a.LiuLayland-2*(math.Sqrt2-1)) > 1e-9 {
+		t.Errorf("LiuLayland = %v, want %v", a.LiuLayland, 2*(math.Sqrt2-1))
+	}
+	if math.Abs(a.Hyperbolic-1.5625) > 1e-9 {
+		t.Errorf("Hyperbolic = %v, want 1.5625", a.Hyperbolic)
+	}
+}
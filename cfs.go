@@ -0,0 +1,195 @@
+package main
+
+import "fmt"
+
+func init() {
+	Register("cfs", "Completely fair", func(opts Options) Scheduler {
+		return funcScheduler{name: "cfs", opts: opts, run: cfs}
+	})
+}
+
+// niceToWeight is the kernel's sched_prio_to_weight table, indexed by nice+20.
+// Each step of nice changes a task's share of the CPU by about 10%.
+var niceToWeight = [40]int64{
+	/* -20 */ 88761, 71755, 56483, 46273, 36291,
+	/* -15 */ 29154, 23254, 18705, 14949, 11916,
+	/* -10 */ 9548, 7620, 6100, 4904, 3906,
+	/*  -5 */ 3121, 2501, 1991, 1586, 1277,
+	/*   0 */ 1024, 820, 655, 526, 423,
+	/*   5 */ 335, 272, 215, 172, 137,
+	/*  10 */ 110, 87, 70, 56, 45,
+	/*  15 */ 36, 29, 23, 18, 15,
+}
+
+// nice0Load is the weight of a task with nice 0.
+const nice0Load = 1024
+
+// niceOf reads a task's Priority as a nice value, clamped to the kernel's range.
+func niceOf(t *Task) int64 {
+	switch {
+	case t.Priority < -20:
+		return -20
+	case t.Priority > 19:
+		return 19
+	}
+	return t.Priority
+}
+
+func weightOf(t *Task) int64 { return niceToWeight[niceOf(t)+20] }
+
+// cfs models the Linux Completely Fair Scheduler. Each process's Priority is
+// its nice value, which sets its weight; the CPU always goes to the process
+// with the smallest virtual runtime, which grows more slowly the heavier the
+// process is. Timeslices are the scheduling period shared out by weight.
+func cfs(opts Options, processes []Process) Result {
+	var policies []*cfsPolicy
+	tasks, gantt := simulate(processes, collect(&policies, func() *cfsPolicy { return newCFSPolicy(opts.CFS) }), opts)
+	r := newResult(tasks, gantt)
+
+	nices := make([]string, len(tasks))
+	weights := make([]string, len(tasks))
+	vruntimes := make([]string, len(tasks))
+	for i, t := range tasks {
+		nices[i] = fmt.Sprint(niceOf(t))
+		weights[i] = fmt.Sprint(weightOf(t))
+		for _, p := range policies {
+			if v, ok := p.vruntime[t]; ok {
+				vruntimes[i] = fmt.Sprintf("%.2f", v)
+			}
+		}
+	}
+	r.Columns = append(r.Columns,
+		Column{Header: "Nice", Values: nices},
+		Column{Header: "Weight", Values: weights},
+		Column{Header: "Vruntime", Values: vruntimes},
+	)
+
+	return r
+}
+
+type cfsPolicy struct {
+	latency        int64
+	minGranularity int64
+
+	queue       readyQueue // ordered by vruntime; stands in for the kernel's red-black tree
+	queueWeight int64
+	vruntime    map[*Task]float64
+	minVruntime float64
+
+	running map[*Task]int64 // each running task's Remaining when it was dispatched
+}
+
+func newCFSPolicy(opts CFSOptions) *cfsPolicy {
+	p := &cfsPolicy{
+		latency:        opts.Latency,
+		minGranularity: opts.MinGranularity,
+		vruntime:       make(map[*Task]float64),
+		running:        make(map[*Task]int64),
+	}
+	if p.latency <= 0 {
+		p.latency = DefaultOptions().CFS.Latency
+	}
+	if p.minGranularity <= 0 {
+		p.minGranularity = DefaultOptions().CFS.MinGranularity
+	}
+	p.queue.less = func(a, b *Task) bool { return p.vruntime[a] < p.vruntime[b] }
+
+	return p
+}
+
+// current is a running task's vruntime including the time it has run so far.
+func (p *cfsPolicy) current(t *Task) float64 {
+	ran := p.running[t] - t.Remaining
+	return p.vruntime[t] + float64(ran*nice0Load)/float64(weightOf(t))
+}
+
+// settle charges a running task for the time it ran and takes it off the CPU.
+func (p *cfsPolicy) settle(t *Task) {
+	p.vruntime[t] = p.current(t)
+	p.running[t] = t.Remaining
+	p.updateMin()
+	delete(p.running, t)
+}
+
+func (p *cfsPolicy) updateMin() {
+	least := -1.0
+	for t := range p.running {
+		if v := p.current(t); least < 0 || v < least {
+			least = v
+		}
+	}
+	if t := p.queue.peek(); t != nil && (least < 0 || p.vruntime[t] < least) {
+		least = p.vruntime[t]
+	}
+	if least > p.minVruntime {
+		p.minVruntime = least
+	}
+}
+
+// Ready queues t. A newly arrived task, or one back from I/O, is placed no
+// earlier than the current minimum vruntime so that it cannot claim the CPU
+// for time it was not there.
+func (p *cfsPolicy) Ready(_ int64, t *Task) {
+	if _, ok := p.running[t]; ok {
+		p.settle(t)
+	} else if v, ok := p.vruntime[t]; !ok || v < p.minVruntime {
+		p.vruntime[t] = p.minVruntime
+	}
+	p.queue.add(t)
+	p.queueWeight += weightOf(t)
+}
+
+func (p *cfsPolicy) Next(int64) *Task {
+	t := p.queue.next()
+	if t == nil {
+		return nil
+	}
+	p.queueWeight -= weightOf(t)
+	p.running[t] = t.Remaining
+	p.updateMin()
+
+	return t
+}
+
+// Slice shares the scheduling period out by weight. The period is the target
+// latency, stretched so that no task runs for less than the minimum granularity.
+func (p *cfsPolicy) Slice(_ int64, t *Task) int64 {
+	n := int64(p.queue.Len() + 1)
+	period := p.latency
+	if n*p.minGranularity > period {
+		period = n * p.minGranularity
+	}
+	slice := period * weightOf(t) / (p.queueWeight + weightOf(t))
+	if slice < 1 {
+		slice = 1
+	}
+	return slice
+}
+
+// Ahead ranks running tasks by their virtual runtime so far.
+func (p *cfsPolicy) Ahead(a, b *Task) bool { return p.current(a) < p.current(b) }
+
+// Preempt lets a newly woken task take the CPU if the running one is ahead of
+// it by more than the minimum granularity, which stops tasks from thrashing.
+func (p *cfsPolicy) Preempt(_ int64, running *Task) bool {
+	next := p.queue.peek()
+	_, ok := p.running[running]
+	return next != nil && ok && p.current(running)-p.vruntime[next] > float64(p.minGranularity)
+}
+
+// Steal gives up a waiting task to another CPU, which places it at its own
+// minimum vruntime as if it had just arrived.
+func (p *cfsPolicy) Steal(_ int64, allowed func(*Task) bool) *Task {
+	t := p.queue.steal(allowed)
+	if t != nil {
+		p.queueWeight -= weightOf(t)
+		delete(p.vruntime, t)
+	}
+	return t
+}
+
+func (p *cfsPolicy) Complete(_ int64, t *Task) {
+	if _, ok := p.running[t]; ok {
+		p.settle(t)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCFS(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		processes     []Process
+		wantGantt     []TimeSlice
+		wantVruntimes []string
+	}{
+		{
+			name: "equal weights split the latency evenly",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 6},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 6},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3},
+				{PID: 2, Start: 3, Stop: 6},
+				{PID: 1, Start: 6, Stop: 9},
+				{PID: 2, Start: 9, Stop: 12},
+			},
+			wantVruntimes: []string{"6.00", "6.00"},
+		},
+		{
+			name: "nice values and wakeup preemption",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, Priority: 0},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 10, Priority: 5},
+				{ProcessID: 3, ArrivalTime: 3, BurstDuration: 4, Priority: -5},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3},
+				{PID: 2, Start: 3, Stop: 4},
+				{PID: 3, Start: 4, Stop: 8},
+				{PID: 1, Start: 8, Stop: 12},
+				{PID: 2, Start: 12, Stop: 14},
+				{PID: 1, Start: 14, Stop: 17},
+				{PID: 2, Start: 17, Stop: 24},
+			},
+			wantVruntimes: []string{"10.00", "30.57", "1.31"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := cfs(DefaultOptions(), tt.processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if vruntimes := got.Columns[2].Values; !reflect.DeepEqual(vruntimes, tt.wantVruntimes) {
+				t.Errorf("vruntimes = %v, want %v", vruntimes, tt.wantVruntimes)
+			}
+		})
+	}
+}
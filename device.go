@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// device is an I/O device that serves one request at a time, in the order its
+// queue gives.
+type device struct {
+	name   string
+	queue  readyQueue
+	busy   *Task
+	since  int64           // when the request in progress started
+	stop   int64           // when the last request finished, or -1 before the first
+	queued map[*Task]int64 // when each waiting task joined the queue
+}
+
+func newDevice(d Device, opts Options) *device {
+	dev := &device{name: d.Name, stop: -1, queued: make(map[*Task]int64)}
+	switch d.Policy {
+	case "sjf":
+		dev.queue.less = func(a, b *Task) bool { return a.Bursts[a.Burst].Duration < b.Bursts[b.Burst].Duration }
+	case "priority":
+		dev.queue.less = morePriority(opts)
+	}
+
+	return dev
+}
+
+// request queues t for the device its current I/O burst names, and reports
+// false if that device is not modelled, in which case the I/O does not
+// contend with anything.
+func (e *engine) request(t *Task) bool {
+	d, ok := e.devices[t.Bursts[t.Burst].Device]
+	if !ok {
+		return false
+	}
+	d.queued[t] = e.now
+	d.queue.add(t)
+	e.serve(d)
+
+	return true
+}
+
+// serve starts the next request on d if it is free, recording any time it sat
+// idle as an IdlePID slice.
+func (e *engine) serve(d *device) {
+	if d.busy != nil {
+		return
+	}
+	t := d.queue.next()
+	if t == nil {
+		return
+	}
+	if d.stop >= 0 && e.now > d.stop {
+		e.gantt = append(e.gantt, TimeSlice{PID: IdlePID, Start: d.stop, Stop: e.now, Device: d.name})
+	}
+	t.IOWait += e.now - d.queued[t]
+	delete(d.queued, t)
+	d.busy, d.since = t, e.now
+	e.push(event{at: e.now + t.Bursts[t.Burst].Duration, kind: eventIO, task: t, dev: d})
+}
+
+// release records the request that has just finished on d and starts the next.
+func (e *engine) release(d *device) {
+	t := d.busy
+	e.gantt = append(e.gantt, TimeSlice{PID: t.ProcessID, Start: d.since, Stop: e.now, Job: t.Job, Device: d.name})
+	d.busy, d.stop = nil, e.now
+	e.serve(d)
+}
+
+// parseDevices parses a list of devices such as "disk=fcfs,net=sjf". A device
+// without a policy is served FCFS.
+func parseDevices(s string) ([]Device, error) {
+	var devices []Device
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(s, ",") {
+		name, policy, _ := strings.Cut(strings.TrimSpace(field), "=")
+		if policy == "" {
+			policy = "fcfs"
+		}
+		switch {
+		case name == "" || name == "cpu" || name == "io":
+			return nil, fmt.Errorf("%w: device %q needs a name other than cpu or io", ErrInvalidArgs, field)
+		case seen[name]:
+			return nil, fmt.Errorf("%w: device %q listed twice", ErrInvalidArgs, name)
+		case policy != "fcfs" && policy != "sjf" && policy != "priority":
+			return nil, fmt.Errorf("%w: device %q: unknown policy %q", ErrInvalidArgs, name, policy)
+		}
+		seen[name] = true
+		devices = append(devices, Device{Name: name, Policy: policy})
+	}
+
+	return devices, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDeviceContention(t *testing.T) {
+	t.Parallel()
+	// Every process wants the disk once its first CPU burst is done, so the
+	// second and third queue behind the first.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2, Bursts: []Burst{{Duration: 1}, {IO: true, Duration: 5, Device: "disk"}, {Duration: 1}}},
+		{ProcessID: 2, BurstDuration: 2, Bursts: []Burst{{Duration: 1}, {IO: true, Duration: 2, Device: "disk"}, {Duration: 1}}},
+		{ProcessID: 3, BurstDuration: 2, Bursts: []Burst{{Duration: 1}, {IO: true, Duration: 1, Device: "disk"}, {Duration: 1}}},
+	}
+	tests := []struct {
+		policy         string
+		wantDisk       []TimeSlice
+		wantCompletion []int64
+		wantIOWait     []int64
+	}{
+		{
+			policy: "fcfs",
+			wantDisk: []TimeSlice{
+				{PID: 1, Start: 1, Stop: 6, Device: "disk"},
+				{PID: 2, Start: 6, Stop: 8, Device: "disk"},
+				{PID: 3, Start: 8, Stop: 9, Device: "disk"},
+			},
+			wantCompletion: []int64{7, 9, 10},
+			wantIOWait:     []int64{0, 4, 5},
+		},
+		{
+			policy: "sjf",
+			wantDisk: []TimeSlice{
+				{PID: 1, Start: 1, Stop: 6, Device: "disk"},
+				{PID: 3, Start: 6, Stop: 7, Device: "disk"},
+				{PID: 2, Start: 7, Stop: 9, Device: "disk"},
+			},
+			wantCompletion: []int64{7, 10, 8},
+			wantIOWait:     []int64{0, 5, 3},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.policy, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultOptions()
+			opts.Devices = []Device{{Name: "disk", Policy: tt.policy}}
+			r := newResult(simulate(processes, ordered(nil, 0, false), opts))
+			if len(r.Devices) != 1 || !reflect.DeepEqual(r.Devices[0].Gantt, tt.wantDisk) {
+				t.Fatalf("devices = %v, want disk %v", r.Devices, tt.wantDisk)
+			}
+			if d := r.Devices[0]; d.Busy != 8 || d.Requests != 3 || d.Utilization != 0.8 {
+				t.Errorf("disk busy = %d, requests = %d, utilization = %v, want 8, 3, 0.8", d.Busy, d.Requests, d.Utilization)
+			}
+			for i, p := range r.Processes {
+				if p.Completion != tt.wantCompletion[i] || p.IOWait != tt.wantIOWait[i] {
+					t.Errorf("process %d completion = %d, I/O wait = %d, want %d, %d", p.ProcessID, p.Completion, p.IOWait, tt.wantCompletion[i], tt.wantIOWait[i])
+				}
+			}
+			for _, s := range r.Gantt {
+				if s.Device != "" {
+					t.Errorf("device slice %v in the CPU chart", s)
+				}
+			}
+		})
+	}
+}
+
+func Test_parseDevices(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    []Device
+		wantErr error
+	}{
+		{
+			name: "success",
+			s:    "disk, net=sjf,tape=priority",
+			want: []Device{
+				{Name: "disk", Policy: "fcfs"},
+				{Name: "net", Policy: "sjf"},
+				{Name: "tape", Policy: "priority"},
+			},
+		},
+		{name: "missing name", s: "=fcfs", wantErr: ErrInvalidArgs},
+		{name: "reserved name", s: "io", wantErr: ErrInvalidArgs},
+		{name: "duplicate", s: "disk,disk=sjf", wantErr: ErrInvalidArgs},
+		{name: "unknown policy", s: "disk=lifo", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseDevices(tt.s)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDevices() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
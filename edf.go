@@ -0,0 +1,24 @@
+package main
+
+func init() {
+	Register("edf", "Earliest deadline first", func(opts Options) Scheduler {
+		return funcScheduler{name: "edf", opts: opts, run: edf}
+	})
+}
+
+// edf always runs the ready process with the earliest deadline, preempting the
+// running process when one with an earlier deadline arrives. Processes without
+// a deadline only run when no process with one is ready.
+func edf(opts Options, processes []Process) Result {
+	return newResult(simulate(processes, ordered(earlierDeadline, 0, true), opts))
+}
+
+func earlierDeadline(a, b *Task) bool {
+	switch {
+	case a.Deadline <= 0:
+		return false
+	case b.Deadline <= 0:
+		return true
+	}
+	return a.Deadline < b.Deadline
+}
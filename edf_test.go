@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEDF(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Deadline: 10},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3, Deadline: 5},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 5, Deadline: 9},
+		{ProcessID: 4, ArrivalTime: 3, BurstDuration: 2},
+	}
+	got := edf(DefaultOptions(), processes)
+
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1},
+		{PID: 2, Start: 1, Stop: 4},
+		{PID: 3, Start: 4, Stop: 9},
+		{PID: 1, Start: 9, Stop: 12},
+		{PID: 4, Start: 12, Stop: 14},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	wantColumns := []Column{
+		{Header: "Deadline", Values: []string{"10", "5", "9", "-"}},
+		{Header: "Lateness", Values: []string{"2", "-1", "0", "-"}, Footer: "Max\n2"},
+		{Header: "Met?", Values: []string{"no", "yes", "yes", "-"}, Footer: "Misses\n1"},
+	}
+	if !reflect.DeepEqual(got.Columns, wantColumns) {
+		t.Errorf("Columns = %v, want %v", got.Columns, wantColumns)
+	}
+	if got.DeadlineMisses != 1 || got.MaxLateness != 2 {
+		t.Errorf("DeadlineMisses, MaxLateness = %d, %d, want 1, 2", got.DeadlineMisses, got.MaxLateness)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("eevdf", "Earliest eligible virtual deadline first", func(opts Options) Scheduler {
+		return funcScheduler{name: "eevdf", opts: opts, run: eevdf}
+	})
+}
+
+// eevdf models the EEVDF scheduler that replaced CFS in Linux 6.6. Processes
+// are weighted by nice value as under cfs, and each asks for the CPU in
+// requests of a fixed size. A process is eligible while its virtual runtime is
+// no further ahead than the weighted average of all runnable processes (its
+// lag is not negative), and among eligible processes the one whose current
+// request has the earliest virtual deadline runs.
+func eevdf(opts Options, processes []Process) Result {
+	var policies []*eevdfPolicy
+	tasks, gantt := simulate(processes, collect(&policies, func() *eevdfPolicy { return newEEVDFPolicy(opts.EEVDF) }), opts)
+	r := newResult(tasks, gantt)
+
+	weights := make([]string, len(tasks))
+	requests := make([]string, len(tasks))
+	vruntimes := make([]string, len(tasks))
+	deadlines := make([]string, len(tasks))
+	for i, t := range tasks {
+		weights[i] = fmt.Sprint(weightOf(t))
+		for _, p := range policies {
+			if s, ok := p.state[t]; ok {
+				requests[i] = fmt.Sprint(p.request(t))
+				vruntimes[i] = fmt.Sprintf("%.2f", s.vruntime)
+				deadlines[i] = fmt.Sprintf("%.2f", s.deadline)
+			}
+		}
+	}
+	r.Columns = append(r.Columns,
+		Column{Header: "Weight", Values: weights},
+		Column{Header: "Request", Values: requests},
+		Column{Header: "Vruntime", Values: vruntimes},
+		Column{Header: "Virtual deadline", Values: deadlines},
+	)
+
+	return r
+}
+
+type eevdfPolicy struct {
+	slice    int64
+	requests map[int64]int64
+
+	ready   []*Task
+	running []*Task
+	state   map[*Task]*eevdfState
+}
+
+type eevdfState struct {
+	vruntime float64
+	deadline float64
+	used     int64 // CPU time used towards the current request
+
+	dispatched int64 // Remaining when the task was last dispatched
+
+	asleep bool    // the task has left the CPU without being requeued
+	lag    float64 // the task's lag when it left
+}
+
+func newEEVDFPolicy(opts EEVDFOptions) *eevdfPolicy {
+	p := &eevdfPolicy{
+		slice:    opts.Slice,
+		requests: opts.Requests,
+		state:    make(map[*Task]*eevdfState),
+	}
+	if p.slice <= 0 {
+		p.slice = DefaultOptions().EEVDF.Slice
+	}
+
+	return p
+}
+
+// request is the size of the CPU requests t makes.
+func (p *eevdfPolicy) request(t *Task) int64 {
+	if r, ok := p.requests[t.ProcessID]; ok && r > 0 {
+		return r
+	}
+	return p.slice
+}
+
+// vruntime is t's virtual runtime, including the time it has run so far if it
+// is on a CPU.
+func (p *eevdfPolicy) vruntime(t *Task) float64 {
+	s := p.state[t]
+	if indexOf(p.running, t) < 0 {
+		return s.vruntime
+	}
+	return s.vruntime + float64((s.dispatched-t.Remaining)*nice0Load)/float64(weightOf(t))
+}
+
+// average is the weighted average virtual runtime of every runnable task,
+// which is the virtual time against which lag is measured.
+func (p *eevdfPolicy) average() float64 {
+	var sum, weight float64
+	for _, t := range p.runnable() {
+		sum += p.vruntime(t) * float64(weightOf(t))
+		weight += float64(weightOf(t))
+	}
+	if weight == 0 {
+		return 0
+	}
+	return sum / weight
+}
+
+func (p *eevdfPolicy) runnable() []*Task {
+	if len(p.running) == 0 {
+		return p.ready
+	}
+	return append(append([]*Task(nil), p.ready...), p.running...)
+}
+
+// settle charges a running task for the time it ran, starting a new request
+// with a later deadline once the current one has been used up.
+func (p *eevdfPolicy) settle(t *Task) {
+	s := p.state[t]
+	ran := s.dispatched - t.Remaining
+	s.vruntime = p.vruntime(t)
+	s.used += ran
+	if r := p.request(t); s.used >= r {
+		s.used = 0
+		s.deadline = s.vruntime + float64(r*nice0Load)/float64(weightOf(t))
+	}
+	i := indexOf(p.running, t)
+	p.running = append(p.running[:i], p.running[i+1:]...)
+}
+
+// Ready queues t. A newly arrived task joins with zero lag, at the current
+// virtual time, and one back from I/O with the lag it had when it blocked.
+func (p *eevdfPolicy) Ready(_ int64, t *Task) {
+	if indexOf(p.running, t) >= 0 {
+		p.settle(t)
+	}
+	s, ok := p.state[t]
+	switch {
+	case !ok:
+		v := p.average()
+		p.state[t] = &eevdfState{vruntime: v, deadline: v + float64(p.request(t)*nice0Load)/float64(weightOf(t))}
+	case s.asleep:
+		shift := p.average() - s.lag - s.vruntime
+		s.vruntime += shift
+		s.deadline += shift
+		s.asleep = false
+	}
+	p.ready = append(p.ready, t)
+}
+
+// best returns the index in p.ready of the eligible task with the earliest
+// virtual deadline, or -1 if there is none. Tasks running on other CPUs count
+// towards the average, so every ready task can be ineligible; with fallback
+// set, best then picks the earliest deadline anyway, as the kernel does,
+// rather than leave a CPU idle.
+func (p *eevdfPolicy) best(fallback bool) int {
+	const epsilon = 1e-9
+	avg := p.average()
+	best, earliest := -1, -1
+	for i, t := range p.ready {
+		if earliest < 0 || p.state[t].deadline < p.state[p.ready[earliest]].deadline {
+			earliest = i
+		}
+		if p.vruntime(t) > avg+epsilon {
+			continue
+		}
+		if best < 0 || p.state[t].deadline < p.state[p.ready[best]].deadline {
+			best = i
+		}
+	}
+	if best < 0 && fallback {
+		return earliest
+	}
+	return best
+}
+
+func (p *eevdfPolicy) Next(int64) *Task {
+	i := p.best(true)
+	if i < 0 {
+		return nil
+	}
+	t := p.ready[i]
+	p.ready = append(p.ready[:i], p.ready[i+1:]...)
+	p.running = append(p.running, t)
+	p.state[t].dispatched = t.Remaining
+
+	return t
+}
+
+// Slice lets a task run for whatever is left of its current request.
+func (p *eevdfPolicy) Slice(_ int64, t *Task) int64 {
+	return p.request(t) - p.state[t].used
+}
+
+// Ahead ranks running tasks by their virtual deadline.
+func (p *eevdfPolicy) Ahead(a, b *Task) bool { return p.state[a].deadline < p.state[b].deadline }
+
+// Preempt hands the CPU to an eligible task whose deadline is earlier than the
+// running task's.
+func (p *eevdfPolicy) Preempt(_ int64, running *Task) bool {
+	i := p.best(false)
+	return i >= 0 && indexOf(p.running, running) >= 0 && p.state[p.ready[i]].deadline < p.state[running].deadline
+}
+
+// Steal gives up the waiting task with the latest deadline that may move. It
+// joins the other CPU with zero lag, as if it had just arrived.
+func (p *eevdfPolicy) Steal(_ int64, allowed func(*Task) bool) *Task {
+	last := -1
+	for i, t := range p.ready {
+		if allowed(t) && (last < 0 || p.state[t].deadline >= p.state[p.ready[last]].deadline) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+	t := p.ready[last]
+	p.ready = append(p.ready[:last], p.ready[last+1:]...)
+	delete(p.state, t)
+
+	return t
+}
+
+// Complete takes t off the CPU and remembers its lag, in case it has only
+// blocked.
+func (p *eevdfPolicy) Complete(_ int64, t *Task) {
+	if indexOf(p.running, t) >= 0 {
+		p.settle(t)
+	}
+	s := p.state[t]
+	s.asleep = true
+	s.lag = p.average() - s.vruntime
+}
+
+// parseRequests parses per-process request sizes such as "1=2,3=5".
+func parseRequests(s string) (map[int64]int64, error) {
+	requests := make(map[int64]int64)
+	for _, field := range strings.Split(s, ",") {
+		pid, size, ok := strings.Cut(strings.TrimSpace(field), "=")
+		id, err := strconv.ParseInt(pid, 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("%w: request %q must be pid=size", ErrInvalidArgs, field)
+		}
+		r, err := strconv.ParseInt(size, 10, 64)
+		if err != nil || r <= 0 {
+			return nil, fmt.Errorf("%w: request %q must be pid=size", ErrInvalidArgs, field)
+		}
+		requests[id] = r
+	}
+
+	return requests, nil
+}
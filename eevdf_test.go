@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEEVDF(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, Priority: 0},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 10, Priority: 5},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 4, Priority: -5},
+	}
+	opts := DefaultOptions()
+	opts.EEVDF.Requests = map[int64]int64{3: 1}
+	got := eevdf(opts, processes)
+
+	// Process 3 joins with zero lag and a short request, so its deadline is
+	// the earliest and it preempts straight away. Process 1 then stays ahead of
+	// the light process 2 until its virtual runtime passes the average.
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 3},
+		{PID: 3, Start: 3, Stop: 7},
+		{PID: 2, Start: 7, Stop: 10},
+		{PID: 1, Start: 10, Stop: 17},
+		{PID: 2, Start: 17, Stop: 24},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	if requests := got.Columns[1].Values; !reflect.DeepEqual(requests, []string{"3", "3", "1"}) {
+		t.Errorf("requests = %v, want [3 3 1]", requests)
+	}
+}
+
+func TestEEVDFCPUs(t *testing.T) {
+	t.Parallel()
+	// While two tasks run, the one left waiting is ahead of the average
+	// virtual runtime, but the free CPU must still take it.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 6},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 6},
+		{ProcessID: 3, ArrivalTime: 0, BurstDuration: 6},
+	}
+	opts := DefaultOptions()
+	opts.CPUs = 2
+	got := eevdf(opts, processes)
+
+	for _, s := range got.Gantt {
+		if s.PID == IdlePID {
+			t.Errorf("CPU %d idle from %d to %d with work ready", s.CPU, s.Start, s.Stop)
+		}
+	}
+	for i, want := range []int64{6, 9, 9} {
+		if c := got.Processes[i].Completion; c != want {
+			t.Errorf("process %d completion = %d, want %d", i+1, c, want)
+		}
+	}
+}
+
+func Test_parseRequests(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    map[int64]int64
+		wantErr error
+	}{
+		{name: "success", s: "1=2, 30=5", want: map[int64]int64{1: 2, 30: 5}},
+		{name: "missing size", s: "1", wantErr: ErrInvalidArgs},
+		{name: "zero size", s: "1=0", wantErr: ErrInvalidArgs},
+		{name: "bad pid", s: "x=1", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseRequests(tt.s)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRequests() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
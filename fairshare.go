@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("fair-share", "Fair share", func(opts Options) Scheduler {
+		return funcScheduler{name: "fair-share", opts: opts, run: fairShare}
+	})
+}
+
+// fairShare divides the CPU between groups in proportion to their shares, then
+// equally between the users of each group, and then equally between each
+// user's processes, a quantum at a time. The group summary compares the share
+// of the CPU each group got while every group had work with the share it is
+// entitled to.
+func fairShare(opts Options, processes []Process) Result {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, p := range processes {
+		if !seen[p.Group] {
+			seen[p.Group] = true
+			groups = append(groups, p.Group)
+		}
+	}
+	var policies []*fairPolicy
+	r := newResult(simulate(processes, collect(&policies, func() *fairPolicy { return newFairPolicy(opts, groups) }), opts))
+
+	r.Groups = groupStats(r, func(ps ProcessStats) string { return groupName(ps.Group) })
+	var shares, contended float64
+	used := make(map[string]float64)
+	for _, g := range groups {
+		shares += float64(shareOf(opts.FairShare, g))
+		for _, p := range policies {
+			used[g] += p.contended[g]
+			contended += p.contended[g]
+		}
+	}
+	for i, g := range groups {
+		r.Groups[i].Entitlement = float64(shareOf(opts.FairShare, g)) / shares
+		if contended > 0 {
+			r.Groups[i].Share = used[g] / contended
+		}
+	}
+
+	return r
+}
+
+// groupName is how a group is shown, with processes that have none in a group
+// of their own.
+func groupName(g string) string {
+	if g == "" {
+		return "(none)"
+	}
+	return g
+}
+
+// shareOf is the share a group is entitled to, 1 unless configured.
+func shareOf(opts FairShareOptions, group string) int64 {
+	if s, ok := opts.Shares[group]; ok && s > 0 {
+		return s
+	}
+	return 1
+}
+
+type fairUser struct{ group, user string }
+
+// fairPolicy keeps a virtual usage for every group, user and process, which
+// grows with the CPU time they are given, and at each level picks whoever has
+// the least. A group's usage grows more slowly the larger its share. Anything
+// that has been idle comes back level with the least used of its peers, so it
+// cannot claim the CPU for time it did not want it.
+type fairPolicy struct {
+	quantum int64
+	opts    FairShareOptions
+	groups  []string
+
+	ready   []*Task
+	running map[*Task]int64 // each running task's Remaining when it was dispatched
+	busy    map[*Task]bool  // whether every group had work when the task was dispatched
+
+	groupUse map[string]float64
+	userUse  map[fairUser]float64
+	taskUse  map[*Task]float64
+
+	contended map[string]float64 // CPU time given to each group while every group had work
+}
+
+func newFairPolicy(opts Options, groups []string) *fairPolicy {
+	return &fairPolicy{
+		quantum:   opts.Quantum,
+		opts:      opts.FairShare,
+		groups:    groups,
+		running:   make(map[*Task]int64),
+		busy:      make(map[*Task]bool),
+		groupUse:  make(map[string]float64),
+		userUse:   make(map[fairUser]float64),
+		taskUse:   make(map[*Task]float64),
+		contended: make(map[string]float64),
+	}
+}
+
+func userOf(t *Task) fairUser { return fairUser{t.Group, t.User} }
+
+// active returns the ready and running tasks.
+func (p *fairPolicy) active() []*Task {
+	tasks := append([]*Task(nil), p.ready...)
+	for t := range p.running {
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
+// settle charges a running task for the time it ran and takes it off the CPU.
+func (p *fairPolicy) settle(t *Task) {
+	ran := float64(p.running[t] - t.Remaining)
+	p.groupUse[t.Group] += ran / float64(shareOf(p.opts, t.Group))
+	p.userUse[userOf(t)] += ran
+	p.taskUse[t] += ran
+	if p.busy[t] {
+		p.contended[t.Group] += ran
+	}
+	delete(p.running, t)
+	delete(p.busy, t)
+}
+
+// Ready queues t. A task that was not just running is first brought level
+// with the least used of the active groups, users and tasks it is compared
+// with, wherever it is behind them.
+func (p *fairPolicy) Ready(_ int64, t *Task) {
+	if _, ok := p.running[t]; ok {
+		p.settle(t)
+		p.ready = append(p.ready, t)
+		return
+	}
+	groupActive, userActive := false, false
+	least := [3]float64{-1, -1, -1}
+	for _, a := range p.active() {
+		if a.Group == t.Group {
+			groupActive = true
+			if a.User == t.User {
+				userActive = true
+				least[2] = lower(least[2], p.taskUse[a])
+			} else {
+				least[1] = lower(least[1], p.userUse[userOf(a)])
+			}
+		} else {
+			least[0] = lower(least[0], p.groupUse[a.Group])
+		}
+	}
+	if !groupActive && least[0] > p.groupUse[t.Group] {
+		p.groupUse[t.Group] = least[0]
+	}
+	if !userActive && least[1] > p.userUse[userOf(t)] {
+		p.userUse[userOf(t)] = least[1]
+	}
+	if least[2] > p.taskUse[t] {
+		p.taskUse[t] = least[2]
+	}
+	p.ready = append(p.ready, t)
+}
+
+// lower is the smaller of least and v, where a negative least means none yet.
+func lower(least, v float64) float64 {
+	if least < 0 || v < least {
+		return v
+	}
+	return least
+}
+
+// Next picks the least used group with a ready task, then the least used user
+// in it, then that user's least used task. Ties go to whoever became ready
+// first.
+func (p *fairPolicy) Next(int64) *Task {
+	best := -1
+	for i, t := range p.ready {
+		if best < 0 || p.before(t, p.ready[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	t := p.ready[best]
+	p.ready = append(p.ready[:best], p.ready[best+1:]...)
+
+	have := make(map[string]bool)
+	for _, a := range p.active() {
+		have[a.Group] = true
+	}
+	have[t.Group] = true
+	p.busy[t] = len(have) == len(p.groups)
+	p.running[t] = t.Remaining
+
+	return t
+}
+
+func (p *fairPolicy) before(a, b *Task) bool {
+	switch {
+	case a.Group != b.Group:
+		return p.groupUse[a.Group] < p.groupUse[b.Group]
+	case a.User != b.User:
+		return p.userUse[userOf(a)] < p.userUse[userOf(b)]
+	}
+	return p.taskUse[a] < p.taskUse[b]
+}
+
+func (p *fairPolicy) Slice(int64, *Task) int64  { return p.quantum }
+func (p *fairPolicy) Preempt(int64, *Task) bool { return false }
+
+func (p *fairPolicy) Complete(_ int64, t *Task) {
+	if _, ok := p.running[t]; ok {
+		p.settle(t)
+	}
+}
+
+// Steal gives up the most recently queued task that may move.
+func (p *fairPolicy) Steal(_ int64, allowed func(*Task) bool) *Task {
+	for i := len(p.ready) - 1; i >= 0; i-- {
+		if t := p.ready[i]; allowed(t) {
+			p.ready = append(p.ready[:i], p.ready[i+1:]...)
+			return t
+		}
+	}
+	return nil
+}
+
+// parseShares parses group shares such as "staff=3,students=1".
+func parseShares(s string) (map[string]int64, error) {
+	shares := make(map[string]int64)
+	for _, field := range strings.Split(s, ",") {
+		group, share, ok := strings.Cut(strings.TrimSpace(field), "=")
+		n, err := strconv.ParseInt(share, 10, 64)
+		if !ok || group == "" || err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: share %q must be group=n with n positive", ErrInvalidArgs, field)
+		}
+		shares[group] = n
+	}
+
+	return shares, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFairShare(t *testing.T) {
+	t.Parallel()
+	// Staff are entitled to three times the CPU of students. Within students,
+	// carol's two processes share what dave's one gets.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 6, User: "alice", Group: "staff"},
+		{ProcessID: 2, BurstDuration: 6, User: "bob", Group: "staff"},
+		{ProcessID: 3, BurstDuration: 6, User: "carol", Group: "students"},
+		{ProcessID: 4, BurstDuration: 6, User: "carol", Group: "students"},
+		{ProcessID: 5, BurstDuration: 6, User: "dave", Group: "students"},
+	}
+	opts := DefaultOptions()
+	opts.Quantum = 2
+	opts.FairShare.Shares = map[string]int64{"staff": 3}
+	got := fairShare(opts, processes)
+
+	var order []int64
+	for _, s := range got.Gantt {
+		order = append(order, s.PID)
+	}
+	if want := []int64{1, 3, 2, 1, 5, 2, 1, 2, 4, 5, 3, 5, 4, 3, 4}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Gantt order = %v, want %v", order, want)
+	}
+	wantGroups := []GroupStats{
+		{Name: "staff", Processes: 2, CPUTime: 12, AveWait: 9, AveTurnaround: 15, AveResponse: 2, Entitlement: 0.75, Share: 0.75},
+		{Name: "students", Processes: 3, CPUTime: 18, AveWait: 64.0 / 3, AveTurnaround: 82.0 / 3, AveResponse: 26.0 / 3, Entitlement: 0.25, Share: 0.25},
+	}
+	if !reflect.DeepEqual(got.Groups, wantGroups) {
+		t.Errorf("Groups = %v, want %v", got.Groups, wantGroups)
+	}
+}
+
+func Test_parseShares(t *testing.T) {
+	t.Parallel()
+	got, err := parseShares("staff=3, students=1")
+	if want := map[string]int64{"staff": 3, "students": 1}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("parseShares() = %v, %v, want %v", got, err, want)
+	}
+	for _, s := range []string{"staff", "=3", "staff=0", "staff=x"} {
+		if _, err := parseShares(s); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("parseShares(%q) error = %v, want %v", s, err, ErrInvalidArgs)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// WorkloadOptions describes a synthetic workload for Generate.
+type WorkloadOptions struct {
+	// Count is the number of processes to generate.
+	Count int
+	// Seed seeds the random source, so that the same options always give
+	// the same workload.
+	Seed int64
+	// Arrivals models the gaps between arrivals: "poisson" with a mean gap,
+	// "uniform" between a low and a high gap, or "bursty" with a group size
+	// and a mean gap between groups whose processes all arrive together.
+	Arrivals Distribution
+	// Bursts models the CPU bursts: "exponential" with a mean, "uniform"
+	// between a low and a high length, or "bimodal" with a short mean, a long
+	// mean and the fraction of bursts that are short. Bursts are at least 1.
+	Bursts Distribution
+	// Priorities models the priorities: "uniform" between a low and a high
+	// priority, or "weighted" with the relative weight of priorities 1, 2, ...
+	Priorities Distribution
+}
+
+// Distribution is a statistical model named by Kind, with parameters that
+// depend on the kind.
+type Distribution struct {
+	Kind   string
+	Params []float64
+}
+
+// DefaultWorkloadOptions returns the workload generated when nothing is
+// overridden.
+func DefaultWorkloadOptions() WorkloadOptions {
+	return WorkloadOptions{
+		Count:      10,
+		Seed:       1,
+		Arrivals:   Distribution{Kind: "poisson", Params: []float64{3}},
+		Bursts:     Distribution{Kind: "exponential", Params: []float64{5}},
+		Priorities: Distribution{Kind: "uniform", Params: []float64{1, 5}},
+	}
+}
+
+// Generate draws a workload of processes, numbered from 1 in order of arrival,
+// with the first arriving at time 0.
+func Generate(opts WorkloadOptions) ([]Process, error) {
+	if opts.Count < 1 {
+		return nil, fmt.Errorf("%w: need at least one process, got %d", ErrInvalidArgs, opts.Count)
+	}
+	if err := opts.Arrivals.check("arrivals", 0, "poisson", "uniform", "bursty"); err != nil {
+		return nil, err
+	}
+	if err := opts.Bursts.check("bursts", 1, "exponential", "uniform", "bimodal"); err != nil {
+		return nil, err
+	}
+	if err := opts.Priorities.check("priorities", math.Inf(-1), "uniform", "weighted"); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	processes := make([]Process, opts.Count)
+	var clock float64
+	for i := range processes {
+		if i > 0 {
+			clock += opts.Arrivals.gap(rng, i)
+		}
+		// exponential and bimodal draws can round down to nothing
+		burst := int64(math.Round(opts.Bursts.draw(rng)))
+		if burst < 1 {
+			burst = 1
+		}
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			ArrivalTime:   int64(math.Round(clock)),
+			BurstDuration: burst,
+			Priority:      int64(opts.Priorities.draw(rng)),
+		}
+	}
+
+	return processes, nil
+}
+
+// maxParam bounds distribution parameters so that their draws stay well within
+// an int64.
+const maxParam = 1 << 53
+
+// check reports whether d is one of kinds with sensible parameters, naming the
+// attribute it models in any error. A uniform range may not start below lowest.
+func (d Distribution) check(attribute string, lowest float64, kinds ...string) error {
+	known := false
+	for _, k := range kinds {
+		known = known || d.Kind == k
+	}
+	if !known {
+		return fmt.Errorf("%w: %s must be one of %s, got %q", ErrInvalidArgs, attribute, strings.Join(kinds, ", "), d.Kind)
+	}
+	want := map[string]int{"poisson": 1, "exponential": 1, "uniform": 2, "bursty": 2, "bimodal": 3}[d.Kind]
+	p := d.Params
+	bad := false
+	switch {
+	case d.Kind == "weighted":
+		var total float64
+		for _, w := range p {
+			bad = bad || w < 0
+			total += w
+		}
+		bad = bad || total <= 0
+	case len(p) != want:
+		bad = true
+	case d.Kind == "uniform":
+		bad = p[0] < lowest || p[0] > p[1] || p[0] != math.Trunc(p[0]) || p[1] != math.Trunc(p[1])
+	case d.Kind == "bursty":
+		bad = p[0] < 1 || p[0] != math.Trunc(p[0]) || p[1] <= 0
+	case d.Kind == "bimodal":
+		bad = p[0] <= 0 || p[1] <= 0 || p[2] < 0 || p[2] > 1
+	default:
+		bad = p[0] <= 0
+	}
+	for _, x := range p {
+		bad = bad || math.IsNaN(x) || math.Abs(x) > maxParam
+	}
+	if bad {
+		return fmt.Errorf("%w: bad %s parameters %v for %s", ErrInvalidArgs, attribute, p, d.Kind)
+	}
+
+	return nil
+}
+
+// gap draws the gap before the arrival of process i, for an arrivals model.
+func (d Distribution) gap(rng *rand.Rand, i int) float64 {
+	if d.Kind == "bursty" {
+		if i%int(d.Params[0]) != 0 {
+			return 0
+		}
+		return rng.ExpFloat64() * d.Params[1]
+	}
+	return d.draw(rng)
+}
+
+func (d Distribution) draw(rng *rand.Rand) float64 {
+	p := d.Params
+	switch d.Kind {
+	case "uniform":
+		return p[0] + float64(rng.Int63n(int64(p[1]-p[0])+1))
+	case "bimodal":
+		if rng.Float64() < p[2] {
+			return rng.ExpFloat64() * p[0]
+		}
+		return rng.ExpFloat64() * p[1]
+	case "weighted":
+		var total float64
+		for _, w := range p {
+			total += w
+		}
+		x := rng.Float64() * total
+		for i, w := range p {
+			if x < w {
+				return float64(i + 1)
+			}
+			x -= w
+		}
+		return float64(len(p))
+	}
+	return rng.ExpFloat64() * p[0]
+}
+
+// parseDistribution parses a distribution such as "uniform:1:5".
+func parseDistribution(s string) (Distribution, error) {
+	fields := strings.Split(strings.TrimSpace(s), ":")
+	d := Distribution{Kind: fields[0]}
+	for _, f := range fields[1:] {
+		x, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return Distribution{}, fmt.Errorf("%w: %q is not kind:param:...", ErrInvalidArgs, s)
+		}
+		d.Params = append(d.Params, x)
+	}
+
+	return d, nil
+}
+
+// WriteProcesses writes processes as id,burst,arrival,priority rows, which
+// loadProcesses reads back.
+func WriteProcesses(w io.Writer, processes []Process) error {
+	cw := csv.NewWriter(w)
+	for _, p := range processes {
+		row := []string{fmt.Sprint(p.ProcessID), fmt.Sprint(p.BurstDuration), fmt.Sprint(p.ArrivalTime), fmt.Sprint(p.Priority)}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// runGenerate is the "generate" command: it parses its own flags from args and
+// writes the workload they describe to w.
+func runGenerate(w io.Writer, args []string) error {
+	opts := DefaultWorkloadOptions()
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	flags.IntVar(&opts.Count, "count", opts.Count, "number of processes to generate")
+	flags.Int64Var(&opts.Seed, "seed", opts.Seed, "seed for the random draws")
+	flags.Func("arrivals", "gaps between arrivals: poisson:mean, uniform:lo:hi or bursty:size:mean (default \"poisson:3\")", func(s string) (err error) {
+		opts.Arrivals, err = parseDistribution(s)
+		return err
+	})
+	flags.Func("bursts", "CPU burst lengths: exponential:mean, uniform:lo:hi or bimodal:short:long:fraction (default \"exponential:5\")", func(s string) (err error) {
+		opts.Bursts, err = parseDistribution(s)
+		return err
+	})
+	flags.Func("priorities", "priorities: uniform:lo:hi or weighted:w1:w2:... for priorities 1, 2, ... (default \"uniform:1:5\")", func(s string) (err error) {
+		opts.Priorities, err = parseDistribution(s)
+		return err
+	})
+	_ = flags.Parse(args)
+
+	processes, err := Generate(opts)
+	if err != nil {
+		return err
+	}
+
+	return WriteProcesses(w, processes)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	opts := WorkloadOptions{
+		Count:      30,
+		Seed:       7,
+		Arrivals:   Distribution{Kind: "bursty", Params: []float64{3, 10}},
+		Bursts:     Distribution{Kind: "uniform", Params: []float64{2, 4}},
+		Priorities: Distribution{Kind: "weighted", Params: []float64{1, 0, 1}},
+	}
+	processes, err := Generate(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != opts.Count || processes[0].ArrivalTime != 0 {
+		t.Fatalf("Generate() = %v, want %d processes starting at 0", processes, opts.Count)
+	}
+	for i, p := range processes {
+		if p.ProcessID != int64(i+1) {
+			t.Errorf("process %d has ID %d", i, p.ProcessID)
+		}
+		if i%3 != 0 && p.ArrivalTime != processes[i-1].ArrivalTime {
+			t.Errorf("process %d arrived at %d, apart from its group at %d", p.ProcessID, p.ArrivalTime, processes[i-1].ArrivalTime)
+		}
+		if i > 0 && p.ArrivalTime < processes[i-1].ArrivalTime {
+			t.Errorf("process %d arrived at %d, before process %d", p.ProcessID, p.ArrivalTime, p.ProcessID-1)
+		}
+		if p.BurstDuration < 2 || p.BurstDuration > 4 {
+			t.Errorf("process %d burst %d, want 2 to 4", p.ProcessID, p.BurstDuration)
+		}
+		if p.Priority != 1 && p.Priority != 3 {
+			t.Errorf("process %d priority %d, want 1 or 3", p.ProcessID, p.Priority)
+		}
+	}
+
+	again, _ := Generate(opts)
+	if !reflect.DeepEqual(again, processes) {
+		t.Errorf("Generate() with the same seed = %v, want %v", again, processes)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteProcesses(&buf, processes); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := loadProcesses(&buf); err != nil || !reflect.DeepEqual(loaded, processes) {
+		t.Errorf("loadProcesses(WriteProcesses()) = %v, %v, want %v", loaded, err, processes)
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		edit func(*WorkloadOptions)
+	}{
+		{name: "no processes", edit: func(o *WorkloadOptions) { o.Count = 0 }},
+		{name: "bursty bursts", edit: func(o *WorkloadOptions) { o.Bursts = Distribution{Kind: "bursty", Params: []float64{2, 3}} }},
+		{name: "missing mean", edit: func(o *WorkloadOptions) { o.Arrivals = Distribution{Kind: "poisson"} }},
+		{name: "negative arrival gaps", edit: func(o *WorkloadOptions) { o.Arrivals = Distribution{Kind: "uniform", Params: []float64{-5, -1}} }},
+		{name: "empty bursts", edit: func(o *WorkloadOptions) { o.Bursts = Distribution{Kind: "uniform", Params: []float64{0, 4}} }},
+		{name: "uniform too wide", edit: func(o *WorkloadOptions) { o.Bursts = Distribution{Kind: "uniform", Params: []float64{1, 1e19}} }},
+		{name: "not a number", edit: func(o *WorkloadOptions) { o.Arrivals = Distribution{Kind: "poisson", Params: []float64{math.NaN()}} }},
+		{name: "infinite mean", edit: func(o *WorkloadOptions) { o.Bursts = Distribution{Kind: "exponential", Params: []float64{math.Inf(1)}} }},
+		{name: "uniform backwards", edit: func(o *WorkloadOptions) { o.Priorities = Distribution{Kind: "uniform", Params: []float64{5, 1}} }},
+		{name: "bimodal fraction", edit: func(o *WorkloadOptions) { o.Bursts = Distribution{Kind: "bimodal", Params: []float64{1, 10, 2}} }},
+		{name: "no weight", edit: func(o *WorkloadOptions) { o.Priorities = Distribution{Kind: "weighted", Params: []float64{0, 0}} }},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultWorkloadOptions()
+			tt.edit(&opts)
+			if _, err := Generate(opts); !errors.Is(err, ErrInvalidArgs) {
+				t.Errorf("Generate() error = %v, want %v", err, ErrInvalidArgs)
+			}
+		})
+	}
+}
+
+func Test_parseDistribution(t *testing.T) {
+	t.Parallel()
+	got, err := parseDistribution("bimodal:2:20:0.8")
+	want := Distribution{Kind: "bimodal", Params: []float64{2, 20, 0.8}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDistribution() = %v, %v, want %v", got, err, want)
+	}
+	if _, err := parseDistribution("uniform:1:x"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
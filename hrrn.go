@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	Register("hrrn", "Highest response ratio next", func(opts Options) Scheduler {
+		return funcScheduler{name: "hrrn", opts: opts, run: hrrn}
+	})
+}
+
+// hrrn runs the ready process with the highest response ratio,
+// (wait + burst) / burst, to completion before picking the next one. The wait
+// counts from when the process last became ready, and the burst is its next
+// CPU burst. Short jobs are favoured as under sjf, but a long job's ratio
+// keeps growing while it waits, so it cannot starve.
+func hrrn(opts Options, processes []Process) Result {
+	var log []string
+	r := newResult(simulate(processes, func() Policy { return &hrrnPolicy{verbose: opts.Verbose, log: &log} }, opts))
+	r.Log = log
+
+	return r
+}
+
+type hrrnPolicy struct {
+	ready   []*Task
+	verbose bool
+	log     *[]string // shared by the policies of every CPU, so it stays in time order
+}
+
+func responseRatio(now int64, t *Task) float64 {
+	return float64(now-t.ReadyAt+t.burst()) / float64(t.burst())
+}
+
+func (p *hrrnPolicy) Ready(_ int64, t *Task) { p.ready = append(p.ready, t) }
+
+// Next picks the highest ratio, leaving ties to whichever became ready first.
+func (p *hrrnPolicy) Next(now int64) *Task {
+	if len(p.ready) == 0 {
+		return nil
+	}
+	best := 0
+	ratios := make([]string, len(p.ready))
+	for i, t := range p.ready {
+		if responseRatio(now, t) > responseRatio(now, p.ready[best]) {
+			best = i
+		}
+		ratios[i] = fmt.Sprintf("%d=%.2f", t.ProcessID, responseRatio(now, t))
+	}
+	t := p.ready[best]
+	p.ready = append(p.ready[:best], p.ready[best+1:]...)
+	if p.verbose {
+		*p.log = append(*p.log, fmt.Sprintf("t=%d: %s -> %d", now, strings.Join(ratios, " "), t.ProcessID))
+	}
+
+	return t
+}
+
+// Steal gives up the task with the lowest response ratio that may move.
+func (p *hrrnPolicy) Steal(now int64, allowed func(*Task) bool) *Task {
+	worst := -1
+	for i, t := range p.ready {
+		if allowed(t) && (worst < 0 || responseRatio(now, t) <= responseRatio(now, p.ready[worst])) {
+			worst = i
+		}
+	}
+	if worst < 0 {
+		return nil
+	}
+	t := p.ready[worst]
+	p.ready = append(p.ready[:worst], p.ready[worst+1:]...)
+
+	return t
+}
+
+func (p *hrrnPolicy) Slice(int64, *Task) int64  { return 0 }
+func (p *hrrnPolicy) Preempt(int64, *Task) bool { return false }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHRRN(t *testing.T) {
+	t.Parallel()
+	// A stream of short jobs would keep process 2 waiting under sjf.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 6},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 2},
+		{ProcessID: 4, ArrivalTime: 4, BurstDuration: 2},
+		{ProcessID: 5, ArrivalTime: 6, BurstDuration: 2},
+		{ProcessID: 6, ArrivalTime: 8, BurstDuration: 2},
+	}
+	opts := DefaultOptions()
+	opts.Verbose = true
+	got := hrrn(opts, processes)
+
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 3},
+		{PID: 3, Start: 3, Stop: 5},
+		{PID: 2, Start: 5, Stop: 11},
+		{PID: 4, Start: 11, Stop: 13},
+		{PID: 5, Start: 13, Stop: 15},
+		{PID: 6, Start: 15, Stop: 17},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	wantLog := []string{
+		"t=0: 1=1.00 -> 1",
+		"t=3: 2=1.33 3=1.50 -> 3",
+		"t=5: 2=1.67 4=1.50 -> 2",
+		"t=11: 4=4.50 5=3.50 6=2.50 -> 4",
+		"t=13: 5=4.50 6=3.50 -> 5",
+		"t=15: 6=4.50 -> 6",
+	}
+	if !reflect.DeepEqual(got.Log, wantLog) {
+		t.Errorf("Log = %q, want %q", got.Log, wantLog)
+	}
+
+	if got := hrrn(DefaultOptions(), processes); got.Log != nil {
+		t.Errorf("Log = %q without Verbose, want none", got.Log)
+	}
+}
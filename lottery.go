@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+func init() {
+	Register("lottery", "Lottery", func(opts Options) Scheduler {
+		return funcScheduler{name: "lottery", opts: opts, run: lottery}
+	})
+}
+
+// lottery holds a draw among the ready processes every quantum, with each
+// process holding as many tickets as its Priority (at least one). Alongside
+// the usual timings it reports the share of the CPU each process could expect
+// from its tickets in the draws it entered, and the share it actually won.
+func lottery(opts Options, processes []Process) Result {
+	var policies []*lotteryPolicy
+	tasks, gantt := simulate(processes, collect(&policies, func() *lotteryPolicy { return newLotteryPolicy(opts) }), opts)
+	r := newResult(tasks, gantt)
+
+	var total float64
+	expected := make(map[*Task]float64)
+	won := make(map[*Task]float64)
+	for _, p := range policies {
+		for t, e := range p.expected {
+			expected[t] += e
+		}
+		for t, w := range p.won {
+			won[t] += w
+			total += w
+		}
+	}
+	tickets := make([]string, len(tasks))
+	shares := make([]string, len(tasks))
+	actual := make([]string, len(tasks))
+	for i, t := range tasks {
+		tickets[i] = fmt.Sprint(ticketsOf(t))
+		shares[i] = fmt.Sprintf("%.1f%%", 100*expected[t]/total)
+		actual[i] = fmt.Sprintf("%.1f%%", 100*won[t]/total)
+	}
+	r.Columns = append(r.Columns,
+		Column{Header: "Tickets", Values: tickets},
+		Column{Header: "Expected", Values: shares},
+		Column{Header: "Actual", Values: actual},
+	)
+
+	return r
+}
+
+type lotteryPolicy struct {
+	rng     *rand.Rand
+	quantum int64
+	pool    []*Task
+
+	expected map[*Task]float64 // CPU time each task's tickets entitled it to
+	won      map[*Task]float64 // CPU time each task actually won
+}
+
+func newLotteryPolicy(opts Options) *lotteryPolicy {
+	return &lotteryPolicy{
+		rng:      rand.New(rand.NewSource(opts.Seed)),
+		quantum:  opts.Quantum,
+		expected: make(map[*Task]float64),
+		won:      make(map[*Task]float64),
+	}
+}
+
+func ticketsOf(t *Task) int64 {
+	if t.Priority < 1 {
+		return 1
+	}
+	return t.Priority
+}
+
+func (p *lotteryPolicy) Ready(_ int64, t *Task) { p.pool = append(p.pool, t) }
+
+// Next draws the winning ticket and charges every contestant its expected
+// share of the quantum about to run.
+func (p *lotteryPolicy) Next(int64) *Task {
+	if len(p.pool) == 0 {
+		return nil
+	}
+	var total int64
+	for _, t := range p.pool {
+		total += ticketsOf(t)
+	}
+	ticket := p.rng.Int63n(total)
+	winner := 0
+	for i, t := range p.pool {
+		if ticket < ticketsOf(t) {
+			winner = i
+			break
+		}
+		ticket -= ticketsOf(t)
+	}
+
+	t := p.pool[winner]
+	run := float64(t.Remaining)
+	if p.quantum > 0 && p.quantum < t.Remaining {
+		run = float64(p.quantum)
+	}
+	for _, c := range p.pool {
+		p.expected[c] += run * float64(ticketsOf(c)) / float64(total)
+	}
+	p.won[t] += run
+	p.pool = append(p.pool[:winner], p.pool[winner+1:]...)
+
+	return t
+}
+
+// Steal gives up the most recently queued task that may move.
+func (p *lotteryPolicy) Steal(_ int64, allowed func(*Task) bool) *Task {
+	for i := len(p.pool) - 1; i >= 0; i-- {
+		if t := p.pool[i]; allowed(t) {
+			p.pool = append(p.pool[:i], p.pool[i+1:]...)
+			return t
+		}
+	}
+	return nil
+}
+
+func (p *lotteryPolicy) Slice(int64, *Task) int64  { return p.quantum }
+func (p *lotteryPolicy) Preempt(int64, *Task) bool { return false }
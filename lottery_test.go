@@ -0,0 +1,40 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLottery(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 20, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 20, Priority: 3},
+	}
+	opts := DefaultOptions()
+	opts.Seed = 42
+
+	first := lottery(opts, processes)
+	if again := lottery(opts, processes); !reflect.DeepEqual(first, again) {
+		t.Fatalf("same seed gave different schedules: %v and %v", first.Gantt, again.Gantt)
+	}
+
+	p := newLotteryPolicy(opts)
+	tasks, _ := simulate(processes, func() Policy { return p }, opts)
+	var expected, won float64
+	for _, task := range tasks {
+		expected += p.expected[task]
+		won += p.won[task]
+		if p.won[task] != float64(task.BurstDuration) {
+			t.Errorf("process %d won %v, want its burst %d", task.ProcessID, p.won[task], task.BurstDuration)
+		}
+	}
+	if math.Abs(expected-won) > 1e-9 {
+		t.Errorf("expected CPU time %v does not add up to the %v handed out", expected, won)
+	}
+	// With three times the tickets, process 2 should finish first.
+	if tasks[1].Completion >= tasks[0].Completion {
+		t.Errorf("process 2 finished at %d, after process 1 at %d", tasks[1].Completion, tasks[0].Completion)
+	}
+}
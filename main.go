@@ -4,38 +4,132 @@ package main
 import (
 	"encoding/csv"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"strconv"
 	"strings"
-
-	"github.com/olekukonko/tablewriter"
 )
 
 func main() {
 	// CLI args
-	f, closeFile, err := openProcessingFile(os.Args...)
+	opts := DefaultOptions()
+	algorithms := flag.String("algorithms", "fcfs,sjf,srtf,priority,preemptive-priority,rr", "comma-separated list of schedulers to run")
+	list := flag.Bool("list", false, "list the available schedulers and exit")
+	periodic := flag.Bool("periodic", false, "read the file as periodic tasks, id,period,wcet[,deadline[,phase]], and schedule their jobs over one hyperperiod")
+	flag.Int64Var(&opts.Quantum, "quantum", opts.Quantum, "time quantum for round-robin")
+	flag.Int64Var(&opts.ContextSwitch, "context-switch", opts.ContextSwitch, "time taken to switch the CPU from one process to another")
+	flag.BoolVar(&opts.HighPriorityFirst, "high-priority-first", opts.HighPriorityFirst, "treat larger priority numbers as more urgent")
+	flag.Int64Var(&opts.AgingInterval, "aging-interval", opts.AgingInterval, "improve a waiting process's priority every this many time units (0 disables aging)")
+	flag.Int64Var(&opts.AgingStep, "aging-step", opts.AgingStep, "how much a waiting process's priority improves each aging interval")
+	flag.IntVar(&opts.CPUs, "cpus", opts.CPUs, "number of CPUs to schedule onto")
+	flag.StringVar(&opts.RunQueue, "run-queue", opts.RunQueue, "with several CPUs, \"global\" for one run queue they all share or \"per-cpu\" for one each")
+	flag.Int64Var(&opts.Balance.Interval, "balance-interval", opts.Balance.Interval, "move waiting processes from busy per-CPU run queues to idle ones every this many time units (0 disables)")
+	flag.Int64Var(&opts.Balance.MigrationCost, "migration-cost", opts.Balance.MigrationCost, "time a process loses warming up a CPU the load balancer moved it to")
+	flag.Float64Var(&opts.Prediction.Tau, "predict-tau", opts.Prediction.Tau, "predicted length of a process's first CPU burst, for the predicted schedulers")
+	flag.Float64Var(&opts.Prediction.Alpha, "predict-alpha", opts.Prediction.Alpha, "weight of the latest CPU burst in the next prediction, from 0 to 1")
+	flag.Func("fair-shares", "comma-separated CPU share of each group under the fair share scheduler, as group=n; unlisted groups get 1", func(s string) (err error) {
+		opts.FairShare.Shares, err = parseShares(s)
+		return err
+	})
+	flag.Func("devices", "comma-separated I/O devices that bursts naming them queue for, as name[=policy] with policy fcfs, sjf or priority, e.g. \"disk,net=sjf\"", func(s string) (err error) {
+		opts.Devices, err = parseDevices(s)
+		return err
+	})
+	flag.BoolVar(&opts.Verbose, "verbose", opts.Verbose, "log how schedulers that support it made each decision")
+	flag.Int64Var(&opts.Seed, "seed", opts.Seed, "seed for randomised schedulers such as lottery")
+	flag.Int64Var(&opts.CFS.Latency, "cfs-latency", opts.CFS.Latency, "CFS target latency: the period in which every runnable process should run once")
+	flag.Int64Var(&opts.CFS.MinGranularity, "cfs-min-granularity", opts.CFS.MinGranularity, "shortest CFS timeslice")
+	flag.Int64Var(&opts.EEVDF.Slice, "eevdf-slice", opts.EEVDF.Slice, "default EEVDF request size")
+	flag.Func("eevdf-requests", "comma-separated EEVDF request sizes for individual processes, as pid=size", func(s string) (err error) {
+		opts.EEVDF.Requests, err = parseRequests(s)
+		return err
+	})
+	flag.Func("mlfq-quanta", "comma-separated MLFQ quantum per level, top level first; 0 runs that level FCFS (default \"2,4,0\")", func(s string) (err error) {
+		opts.MLFQ.Quanta, err = parseInt64List(s)
+		return err
+	})
+	flag.Func("mlq-classes", "comma-separated multilevel queue classes, highest priority first, as name=policy[:quantum] with policy fcfs, sjf or rr (default \"system=fcfs,interactive=rr:2,batch=sjf\")", func(s string) (err error) {
+		opts.MLQ.Classes, err = parseClassQueues(s)
+		return err
+	})
+	flag.Func("mlq-shares", "comma-separated share of each multilevel queue class when time-slicing between them, e.g. 80,20 (default: fixed priority)", func(s string) (err error) {
+		opts.MLQ.Shares, err = parseInt64List(s)
+		return err
+	})
+	flag.Int64Var(&opts.MLQ.Cycle, "mlq-cycle", opts.MLQ.Cycle, "length of one round of turns when time-slicing between multilevel queue classes")
+	flag.Int64Var(&opts.MLFQ.BoostInterval, "mlfq-boost", opts.MLFQ.BoostInterval, "move every MLFQ process back to the top level every this many time units (0 disables)")
+	flag.Parse()
+	if err := opts.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	if *list {
+		listSchedulers(os.Stdout)
+		return
+	}
+
+	// "generate [flags]" writes a synthetic workload instead of scheduling one
+	args := flag.Args()
+	if len(args) > 0 && args[0] == "generate" {
+		if err := runGenerate(os.Stdout, args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// "analyze tasks.csv" checks a periodic task set instead of scheduling it
+	analyze := len(args) > 0 && args[0] == "analyze"
+	if analyze {
+		args = args[1:]
+	}
+	f, closeFile, err := openProcessingFile(append([]string{os.Args[0]}, args...)...)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer closeFile()
 
+	if analyze {
+		tasks, err := loadPeriodicTasks(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		a := Analyze(tasks)
+		met, err := checkAnalysis(opts, tasks, a)
+		if met != nil {
+			RenderAnalysis(os.Stdout, a, met)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Load and parse processes
-	processes, err := loadProcesses(f)
+	var processes []Process
+	if *periodic {
+		var tasks []PeriodicTask
+		tasks, err = loadPeriodicTasks(f)
+		processes = expandJobs(tasks)
+	} else {
+		processes, err = loadProcesses(f)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err := opts.ValidateProcesses(processes); err != nil {
+		log.Fatal(err)
+	}
 
-	// First-come, first-serve scheduling
-	FCFSSchedule(os.Stdout, "First-come, first-serve", processes)
-
-	SJFSchedule(os.Stdout, "Shortest-job-first", processes)
-	
-	SJFPrioritySchedule(os.Stdout, "Priority", processes)
-	//
-	RRSchedule(os.Stdout, "Round-robin", processes)
+	for _, name := range strings.Split(*algorithms, ",") {
+		reg, err := Lookup(strings.TrimSpace(name))
+		if err != nil {
+			log.Fatal(err)
+		}
+		Render(os.Stdout, reg.Title, reg.New(opts).Run(processes))
+	}
 }
 
 func openProcessingFile(args ...string) (*os.File, func(), error) {
@@ -58,452 +152,183 @@ func openProcessingFile(args ...string) (*os.File, func(), error) {
 
 type (
 	Process struct {
-		ProcessID     int64
-		ArrivalTime   int64
+		ProcessID   int64
+		ArrivalTime int64
+		// BurstDuration is the total CPU time the process needs.
 		BurstDuration int64
-		Priority      int64
+		// Bursts alternates CPU and I/O bursts, starting and ending on the
+		// CPU. It is empty for a CPU-bound process, which needs its
+		// BurstDuration in one go.
+		Bursts   []Burst
+		Priority int64
+		// Class names the queue the process belongs to under the multilevel
+		// queue scheduler, e.g. "system", "interactive" or "batch".
+		Class string
+		// Deadline is the time by which the process should complete, or 0
+		// if it has none.
+		Deadline int64
+		// Period and Job are set when the process is one job of a periodic
+		// task: Period is the task's period and Job numbers its jobs from 1.
+		Period int64
+		Job    int64
+		// Affinity is a bitmask of the CPUs the process may run on, with bit
+		// i standing for CPU i, or 0 if it may run on any of them.
+		Affinity int64
+		// User and Group name who the process runs for, under the fair
+		// share scheduler.
+		User  string
+		Group string
+	}
+	// Burst is a spell of running on the CPU, or of waiting on I/O.
+	Burst struct {
+		IO       bool
+		Duration int64
+		// Device names the device an I/O burst uses, or is empty for I/O
+		// that does not contend for a device.
+		Device string
 	}
 	TimeSlice struct {
 		PID   int64
 		Start int64
 		Stop  int64
+		// Level is the queue level the slice ran at, for multilevel schedulers.
+		Level int
+		// Job is the job of a periodic task the slice ran, or 0.
+		Job int64
+		// CPU is the processor the slice ran on.
+		CPU int
+		// Device is the I/O device the slice used, or empty for a slice on
+		// a CPU.
+		Device string
 	}
 )
 
+// ioTime is the total time the process spends waiting on I/O.
+func (p Process) ioTime() int64 {
+	var total int64
+	for _, b := range p.Bursts {
+		if b.IO {
+			total += b.Duration
+		}
+	}
+	return total
+}
+
+// allowedOn reports whether the process's affinity lets it run on cpu.
+func (p Process) allowedOn(cpu int) bool {
+	return p.Affinity == 0 || p.Affinity&(1<<cpu) != 0
+}
+
 //region Schedulers
 
+func init() {
+	Register("fcfs", "First-come, first-serve", func(opts Options) Scheduler {
+		return funcScheduler{name: "fcfs", opts: opts, run: fcfs}
+	})
+	Register("sjf", "Shortest-job-first", func(opts Options) Scheduler {
+		return funcScheduler{name: "sjf", opts: opts, run: sjf}
+	})
+	Register("srtf", "Shortest-remaining-time-first", func(opts Options) Scheduler {
+		return funcScheduler{name: "srtf", opts: opts, run: srtf}
+	})
+	Register("sjf-priority", "Shortest-job-first, priority tie-break", func(opts Options) Scheduler {
+		return funcScheduler{name: "sjf-priority", opts: opts, run: sjfPriority}
+	})
+	Register("priority", "Priority", func(opts Options) Scheduler {
+		return funcScheduler{name: "priority", opts: opts, run: priority}
+	})
+	Register("preemptive-priority", "Preemptive priority", func(opts Options) Scheduler {
+		return funcScheduler{name: "preemptive-priority", opts: opts, run: preemptivePriority}
+	})
+	Register("rr", "Round-robin", func(opts Options) Scheduler {
+		return funcScheduler{name: "rr", opts: opts, run: rr}
+	})
+}
+
 // FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
 // • an output writer
 // • a title for the chart
 // • a slice of processes
 func FCFSSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-	)
-	for i := range processes {
-		if processes[i].ArrivalTime > 0 {
-			waitingTime = serviceTime - processes[i].ArrivalTime
-		}
-		totalWait += float64(waitingTime)
-
-		start := waitingTime + processes[i].ArrivalTime
-		// print("\nCurrent gantt append: ProcessID: ", processes[i].ProcessID, ", WaitingTime: ", waitingTime, ", start: ", start, ", stop: ", serviceTime, ", Burst Duration: ", processes[i].BurstDuration, "\n")
-
-		turnaround := processes[i].BurstDuration + waitingTime
-		totalTurnaround += float64(turnaround)
-
-		completion := processes[i].BurstDuration + processes[i].ArrivalTime + waitingTime
-		lastCompletion = float64(completion)
-
-		schedule[i] = []string{
-			fmt.Sprint(processes[i].ProcessID),
-			fmt.Sprint(processes[i].Priority),
-			fmt.Sprint(processes[i].BurstDuration),
-			fmt.Sprint(processes[i].ArrivalTime),
-			fmt.Sprint(waitingTime),
-			fmt.Sprint(turnaround),
-			fmt.Sprint(completion),
-		}
-		serviceTime += processes[i].BurstDuration
-
-		gantt = append(gantt, TimeSlice{
-			PID:   processes[i].ProcessID,
-			Start: start,
-			Stop:  serviceTime,
-		})
-	}
-
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	Render(w, title, fcfs(DefaultOptions(), processes))
 }
 
-// a basic removal funciton in order to remove the identified process by its ProcessID
-// I used the processID itself instead of the process as a whole as in some of the functions 
-// change values in the process making the process itself a different variable when searched for
-func remove(s []Process, j int64) []Process {
-    var k int64		// identifier for position
-	for i := range s{		// search the slice for the identifiable process
-		if s[i].ProcessID == j{		// if the id matches
-			k = int64(i)		// the k value holds the position
-			break		// leave the search
-		}
-	}
-	s[k] = s[len(s)-1]		// adjust the slice to ensure the slice no longer contains the process
-    return s[:len(s)-1]		// return the adjusted slice value so that the slice is no longer inside the slice
+// SJFSchedule outputs a shortest-job-first schedule in the same form as FCFSSchedule.
+func SJFSchedule(w io.Writer, title string, processes []Process) {
+	Render(w, title, sjf(DefaultOptions(), processes))
 }
 
-// basic find matching process and its position in the slice function
-func find(s []Process, i int64) int64 {
-	for j:=0; j<len(s); j++{		// search the slice
-		if s[j].ProcessID == i{		// if the current process matches the process id of the intended target
-			return int64(j)			// return the position in which the process is found in the slice
-		}
-	}
-	return 0						// if there is no matching position the value 0 is returned as a control return
+// HRRNSchedule outputs a highest-response-ratio-next schedule in the same form
+// as FCFSSchedule.
+func HRRNSchedule(w io.Writer, title string, processes []Process) {
+	Render(w, title, hrrn(DefaultOptions(), processes))
 }
 
-// function to find how many times a process is identified in a given slice
-func findRep(s []Process, i int64) int64 {
-	var k int64 = 0					// variable counting how many times a process is identified
-	for j := range s {				// search the whole slice
-		if s[j].ProcessID == i {			// if the current process has the same process id
-			k++						// increment k to match the count of matching processes
-		}
-	}
-	return k						// return the count of matching processes
+// SJFPrioritySchedule outputs a shortest-job-first schedule that breaks ties by priority.
+func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
+	Render(w, title, sjfPriority(DefaultOptions(), processes))
 }
 
-// search function used to search if a process is present in a process slice
-func searchP(slic []Process, Pr Process) bool{
-	for a := range slic{		// search the slice for the process one by one
-		if slic[a] == Pr {		// if the current process is the same
-			return true			// return true, indicating the process is present in the given slice
-		}
-	}
-	return false				// if the process was not identified, return false suggesting no match was found
+// RRSchedule outputs a round-robin schedule using the default time quantum.
+// Use the "rr" scheduler from the registry to pick a different one.
+func RRSchedule(w io.Writer, title string, processes []Process) {
+	Render(w, title, rr(DefaultOptions(), processes))
 }
 
-// Shortest Job First schedule function
-func SJFSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		low				int64 = 100
-		change			bool = false
-		currentProc		int64 = 0
-		lengthP 		int64 = int64(len(processes)) + int64(1)
-		starts			= make([]int64, 100)
-		schedule        = make([][]string, lengthP)
-		gantt           = make([]TimeSlice, 0)
-		arrived			= make([]Process, len(processes))
-		inProgress 		Process
-		templateP		Process
-		ran				= make([]Process, len(processes))
-	)
-	inProgress.ProcessID = -1		// placeholder process variables are loaded with identifiers to ensure they are not mis-adjusted
-	templateP.ProcessID = -1
-	templateP.BurstDuration = 100
-
-	for i := 0; i<=99; i++ {		// timer for loop to reflect each second as it passes
-		low = 100					// placeholder lowest burst duration value is loaded
-		change = false				// if the value currently running is changed this bool reflects it
-		for a := range processes{		// load all applicable processes into the ready to run stack, but only if they have not run already
-			if processes[a].ArrivalTime <= int64(i) && !searchP(ran, processes[a]){
-				arrived = append(arrived, processes[a])
-			}
-		}
-		if inProgress.ProcessID >= 0 && !searchP(ran, inProgress){		// the lowest burst duration should be what is currently runnning, 
-			low = inProgress.BurstDuration								// but only if this is an actual process, not a placeholder
-		}
-		for b := range arrived {										// loop to determine if the currently running process is not the top process anymore
-			if b < len(arrived){
-				if arrived[b].BurstDuration < low && !searchP(ran, arrived[b]){
-					inProgress = arrived[b]								// if the arrived process is lower in burst duration than the current in progress process
-					change = true										// pre-empt it and reflect the change in the bool variable
-				}
-			}
-		}
-		if change == true {												// if a new process started, record it's start value in the starts slice
-			starts[inProgress.ProcessID] = int64(i)
-		}
-		if inProgress.ProcessID >= 0 && !searchP(ran, inProgress){		// if the current in progress process has completed its run and is not already recorded as complete
-			if int64(i) == inProgress.BurstDuration + starts[inProgress.ProcessID] {
-				currentProc += 1		// increment the schedule counter
-				waitingTime = starts[inProgress.ProcessID] - inProgress.ArrivalTime		// waiting time is equal to the start time - the arrival time
-				totalWait += float64(waitingTime)		// increment total wait to reflect the change in wait time
-				start := waitingTime + inProgress.ArrivalTime		// burst + start - arrival, but actually the start time for the process
-				turnaround := inProgress.BurstDuration + waitingTime		// burst duration + the waiting time
-				totalTurnaround += float64(turnaround)			// increment the turnaround counter
-				completion := inProgress.BurstDuration + inProgress.ArrivalTime + waitingTime		// add the completion total time
-				lastCompletion = float64(completion)		// last completion is currently this completion
-				schedule[currentProc] = []string{		// write the schedule value based on the schedule counter
-					fmt.Sprint(inProgress.ProcessID),
-					fmt.Sprint(inProgress.Priority),
-					fmt.Sprint(inProgress.BurstDuration),
-					fmt.Sprint(inProgress.ArrivalTime),
-					fmt.Sprint(waitingTime),
-					fmt.Sprint(turnaround),
-					fmt.Sprint(completion),
-				}
-				serviceTime = int64(i)		// servicetime is the current time in seconds
-				gantt = append(gantt, TimeSlice{		// add the current stretch of time
-					PID:   inProgress.ProcessID,
-					Start: start,
-					Stop:  serviceTime,
-				})
-				ran = append(ran, inProgress)		// add this process to the run counter
-				inProgress = templateP		// in progress is now a placeholder variable again
-				for b := range arrived {		// set the new in progress process
-					if b < len(arrived){
-						if arrived[b].BurstDuration < low && !searchP(ran, arrived[b]){
-							inProgress = arrived[b]
-							change = true
-						}
-					}
-				}
-				if change == true {
-					starts[inProgress.ProcessID] = int64(i)
-				}
-			}
-		}
-	}
-
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+func fcfs(opts Options, processes []Process) Result {
+	return newResult(simulate(processes, ordered(nil, 0, false), opts))
 }
 
-// Shortest Job First schedule function but with priority added
-// since I used the exact same code for this except for in cases of conflicting burst durations
-// with the priority value being the tie-breaker, I will only comment the section I changed.
-// read above to check about the other stuff
-func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		low				int64 = 100
-		change			bool = false
-		currentProc		int64 = 0
-		lengthP 		int64 = int64(len(processes)) + int64(1)
-		starts			= make([]int64, 100)
-		schedule        = make([][]string, lengthP)
-		gantt           = make([]TimeSlice, 0)
-		arrived			= make([]Process, len(processes))
-		inProgress 		Process
-		templateP		Process
-		ran				= make([]Process, len(processes))
-	)
-	inProgress.ProcessID = -1
-	templateP.ProcessID = -1
-	templateP.BurstDuration = 100
-
-	for i := 0; i<=99; i++ {	
-		low = 100
-		change = false
-		for a := range processes{
-			if processes[a].ArrivalTime <= int64(i) && !searchP(ran, processes[a]){
-				arrived = append(arrived, processes[a])
-			}
-		}
-		if inProgress.ProcessID >= 0 && !searchP(ran, inProgress){
-			low = inProgress.BurstDuration
-		}
-		for b := range arrived {		// same loop as in the Shortest Job First Function, but a tie-breaker value is the priority value
-			if b < len(arrived){
-				if arrived[b].BurstDuration == low && !searchP(ran, arrived[b]){		// if both have equal burst durations
-					if arrived[b].Priority < inProgress.Priority{		// check which has a higher priority
-						inProgress = arrived[b]		
-					} else {
-						continue
-					}
-				}
-				if arrived[b].BurstDuration < low && !searchP(ran, arrived[b]){
-					inProgress = arrived[b]
-					change = true
-				}
-			}
-		}
-		if change == true {
-			starts[inProgress.ProcessID] = int64(i)
-		}
-		if inProgress.ProcessID >= 0 && !searchP(ran, inProgress){
-			if int64(i) == inProgress.BurstDuration + starts[inProgress.ProcessID] {
-				currentProc += 1
-				waitingTime = starts[inProgress.ProcessID] - inProgress.ArrivalTime
-				totalWait += float64(waitingTime)
-				start := waitingTime + inProgress.ArrivalTime		// burst + start - arrival
-				turnaround := inProgress.BurstDuration + waitingTime
-				totalTurnaround += float64(turnaround)
-				completion := inProgress.BurstDuration + inProgress.ArrivalTime + waitingTime
-				lastCompletion = float64(completion)
-				schedule[currentProc] = []string{
-					fmt.Sprint(inProgress.ProcessID),
-					fmt.Sprint(inProgress.Priority),
-					fmt.Sprint(inProgress.BurstDuration),
-					fmt.Sprint(inProgress.ArrivalTime),
-					fmt.Sprint(waitingTime),
-					fmt.Sprint(turnaround),
-					fmt.Sprint(completion),
-				}
-				serviceTime = int64(i)
-				gantt = append(gantt, TimeSlice{
-					PID:   inProgress.ProcessID,
-					Start: start,
-					Stop:  serviceTime,
-				})
-				ran = append(ran, inProgress)
-				inProgress = templateP
-				for b := range arrived {
-					if b < len(arrived){
-						if arrived[b].BurstDuration < low && !searchP(ran, arrived[b]){
-							inProgress = arrived[b]
-							change = true
-						}
-					}
-				}
-				if change == true {
-					starts[inProgress.ProcessID] = int64(i)
-				}
-			}
-		}
-	}
+// sjf runs the ready process with the shortest next CPU burst to completion
+// before picking the next one.
+func sjf(opts Options, processes []Process) Result {
+	return newResult(simulate(processes, ordered(shorterBurst, 0, false), opts))
+}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+// srtf is preemptive sjf: a newly ready process takes the CPU whenever it needs
+// less time than the running process has left.
+func srtf(opts Options, processes []Process) Result {
+	return newResult(simulate(processes, ordered(lessRemaining, 0, true), opts))
 }
 
-// I rewrote this function to reflect the trade-off system used to constantly partially perform each process
-// the function now uses a queue which is consistently adjusted each time the maxTime variable is run on a process
-// the maxTime variable could be adjusted to match a different time quantum should the function need to be adjusted
-func RRSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-		maxTime			int64 = 1
-		secondQ			= make([]Process, 0)
-		temp1			Process
-		lstRan			= make([]Process, len(processes))
-		starts			= make([]int64, 100)
-		currentProc		int64 = -1
-	)
-	for z := range starts{			// initializes the starts queue with -1 as a start time so that -1 can be used as a blank flag
-		starts[z] = -1				// as no start time would be -1
-	}
-	for a := 0; a<100; a++{			// a = seconds and is therefore the timer for the function
-		for b := range processes{
-			if processes[b].ArrivalTime <= int64(a) && findRep(lstRan, processes[b].ProcessID) == 0 && findRep(secondQ, processes[b].ProcessID) == 0{
-				secondQ = append(secondQ, processes[b])		// if the current process is arrived, not already run, or already in the secondary queue,
-			} else {		// add it to the queue
-				continue
-			}
+// sjfPriority is sjf with equal bursts broken by priority.
+func sjfPriority(opts Options, processes []Process) Result {
+	return newResult(simulate(processes, ordered(func(a, b *Task) bool {
+		if a.burst() != b.burst() {
+			return a.burst() < b.burst()
 		}
-		if len(secondQ) != 0 {		// if the secondary queue is not empty
-			if secondQ[0].ProcessID == 0{
-				secondQ = remove(secondQ, 0)		// remove the current value in secondary queue if it is a processID of 0, which should not be in the list
-			}
-			if starts[secondQ[0].ProcessID] == -1 {		// if the start variable for the currently running process is the placeholder -1, record the accurate start time
-				starts[secondQ[0].ProcessID] = int64(a)
-			}
-			secondQ[0].BurstDuration -= int64(maxTime)		// remove the time from the burst duration to reflect the running time
-			temp1 = secondQ[0]		// placeholder copy
-			gantt = append(gantt, TimeSlice{		// gantt adjust for the current runtime
-				PID:   temp1.ProcessID,
-				Start: int64(a),
-				Stop:  serviceTime,
-			})
-			if secondQ[0].BurstDuration == 0 {		// if the process finished
-				lstRan = append(lstRan, secondQ[0])		// add to the complete list
-				secondQ = remove(secondQ, secondQ[0].ProcessID)		// remove from the secondary queue to avoid repeats
-				currentProc += 1		// increment the schedule counter
-				waitingTime = starts[temp1.ProcessID] - temp1.ArrivalTime		// the waiting time is the start value - arrival time
-				totalWait += float64(waitingTime)		// increment the totalwait
-				turnaround := int64(a) + waitingTime		// turnaround is the wait time + the completion time
-				totalTurnaround += float64(turnaround)		// increment the total turnaround time
-				completion := int64(a)		// completion is the current time
-				lastCompletion = float64(completion)		// last completion is the current completion until adjusted later
-				var temp2 int64 = 0		// temporary int variable for search
-				for d := range processes {
-					if processes[d].ProcessID == temp1.ProcessID {
-						temp2 = int64(d)	// when a matching position is found, save that position with temp2
-						break
-					}
-				}
-				schedule[currentProc] = []string{		// write the schedule
-					fmt.Sprint(temp1.ProcessID),
-					fmt.Sprint(temp1.Priority),
-					fmt.Sprint(processes[temp2].BurstDuration),
-					fmt.Sprint(temp1.ArrivalTime),
-					fmt.Sprint(waitingTime),
-					fmt.Sprint(turnaround),
-					fmt.Sprint(completion),
-				}
-				serviceTime = int64(a)
-			} else{		// if not complete continue swapping the processes from the front to the rear after they have been incremented for running
-				secondQ = remove(secondQ, temp1.ProcessID)
-				secondQ = append(secondQ, temp1)
-				continue
-			}
-		}
-	} 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+		return a.Priority < b.Priority
+	}, 0, false), opts))
 }
 
-//endregion
-
-//region Output helpers
+// priority runs the most urgent ready process to completion before picking the
+// next one. Processes of equal priority run in the order they became ready.
+func priority(opts Options, processes []Process) Result {
+	return agedResult(opts, newResult(simulate(processes, func() Policy { return newPriorityPolicy(opts, false) }, opts)))
+}
 
-func outputTitle(w io.Writer, title string) {
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
-	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+// preemptivePriority is priority, except that a newly ready process takes the
+// CPU as soon as it is more urgent than the running one.
+func preemptivePriority(opts Options, processes []Process) Result {
+	return agedResult(opts, newResult(simulate(processes, func() Policy { return newPriorityPolicy(opts, true) }, opts)))
 }
 
-func outputGantt(w io.Writer, gantt []TimeSlice) {
-	_, _ = fmt.Fprintln(w, "Gantt schedule")
-	_, _ = fmt.Fprint(w, "|")
-	for i := range gantt {
-		pid := fmt.Sprint(gantt[i].PID)
-		padding := strings.Repeat(" ", (8-len(pid))/2)
-		_, _ = fmt.Fprint(w, padding, pid, padding, "|")
-	}
-	_, _ = fmt.Fprintln(w)
-	for i := range gantt {
-		_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Start), "\t")
-		if len(gantt)-1 == i {
-			_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Stop))
-		}
-	}
-	_, _ = fmt.Fprintf(w, "\n\n")
+// rr gives each ready process the Quantum option's worth of CPU in turn,
+// putting it back at the end of the queue if it has not finished.
+func rr(opts Options, processes []Process) Result {
+	return newResult(simulate(processes, ordered(nil, opts.Quantum, false), opts))
 }
 
-func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64) {
-	_, _ = fmt.Fprintln(w, "Schedule table")
-	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"})
-	table.AppendBulk(rows)
-	table.SetFooter([]string{"", "", "", "",
-		fmt.Sprintf("Average\n%.2f", wait),
-		fmt.Sprintf("Average\n%.2f", turnaround),
-		fmt.Sprintf("Throughput\n%.2f/t", throughput)})
-	table.Render()
+func shorterBurst(a, b *Task) bool  { return a.burst() < b.burst() }
+func lessRemaining(a, b *Task) bool { return a.Remaining < b.Remaining }
+
+// morePriority orders tasks by effective priority, treating lower numbers as
+// more urgent unless the HighPriorityFirst option is set.
+func morePriority(opts Options) func(a, b *Task) bool {
+	if opts.HighPriorityFirst {
+		return func(a, b *Task) bool { return a.Effective > b.Effective }
+	}
+	return func(a, b *Task) bool { return a.Effective < b.Effective }
 }
 
 //endregion
@@ -521,16 +346,85 @@ func loadProcesses(r io.Reader) ([]Process, error) {
 	processes := make([]Process, len(rows))
 	for i := range rows {
 		processes[i].ProcessID = mustStrToInt(rows[i][0])
-		processes[i].BurstDuration = mustStrToInt(rows[i][1])
+		if strings.Contains(rows[i][1], ":") {
+			processes[i].Bursts, err = parseBursts(rows[i][1])
+			if err != nil {
+				return nil, err
+			}
+			for _, b := range processes[i].Bursts {
+				if !b.IO {
+					processes[i].BurstDuration += b.Duration
+				}
+			}
+		} else {
+			processes[i].BurstDuration = mustStrToInt(rows[i][1])
+		}
 		processes[i].ArrivalTime = mustStrToInt(rows[i][2])
-		if len(rows[i]) == 4 {
+		if len(rows[i]) >= 4 {
 			processes[i].Priority = mustStrToInt(rows[i][3])
 		}
+		if len(rows[i]) >= 5 {
+			processes[i].Class = strings.TrimSpace(rows[i][4])
+		}
+		if len(rows[i]) >= 6 {
+			processes[i].Deadline = optStrToInt(rows[i][5])
+		}
+		if len(rows[i]) >= 7 {
+			processes[i].Affinity = optStrToInt(rows[i][6])
+		}
+		if len(rows[i]) >= 8 {
+			processes[i].User = strings.TrimSpace(rows[i][7])
+		}
+		if len(rows[i]) >= 9 {
+			processes[i].Group = strings.TrimSpace(rows[i][8])
+		}
 	}
 
 	return processes, nil
 }
 
+// parseBursts parses a sequence of bursts such as "cpu:5,disk:3,cpu:2", which
+// must alternate between CPU and I/O and start and end on the CPU. An I/O
+// burst names the device it uses, or is "io" to use none in particular.
+func parseBursts(s string) ([]Burst, error) {
+	fields := strings.Split(s, ",")
+	bursts := make([]Burst, len(fields))
+	for i, f := range fields {
+		kind, duration, _ := strings.Cut(strings.TrimSpace(f), ":")
+		d, err := strconv.ParseInt(duration, 10, 64)
+		if err != nil || d <= 0 || kind == "" {
+			return nil, fmt.Errorf("%w: burst %q must be cpu:n, io:n or device:n", ErrInvalidArgs, f)
+		}
+		bursts[i] = Burst{IO: kind != "cpu", Duration: d}
+		if kind != "cpu" && kind != "io" {
+			bursts[i].Device = kind
+		}
+		if bursts[i].IO != (i%2 == 1) {
+			return nil, fmt.Errorf("%w: bursts %q must alternate cpu and io, starting with cpu", ErrInvalidArgs, s)
+		}
+	}
+	if bursts[len(bursts)-1].IO {
+		return nil, fmt.Errorf("%w: bursts %q must end with cpu", ErrInvalidArgs, s)
+	}
+
+	return bursts, nil
+}
+
+// parseInt64List parses a comma-separated list of integers.
+func parseInt64List(s string) ([]int64, error) {
+	fields := strings.Split(s, ",")
+	list := make([]int64, len(fields))
+	for i, f := range fields {
+		n, err := strconv.ParseInt(strings.TrimSpace(f), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a list of integers", ErrInvalidArgs, s)
+		}
+		list[i] = n
+	}
+
+	return list, nil
+}
+
 func mustStrToInt(s string) int64 {
 	i, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
@@ -541,4 +435,13 @@ func mustStrToInt(s string) int64 {
 	return i
 }
 
+// optStrToInt is mustStrToInt for optional columns, which may be left empty.
+func optStrToInt(s string) int64 {
+	if s = strings.TrimSpace(s); s == "" {
+		return 0
+	}
+
+	return mustStrToInt(s)
+}
+
 //endregion
@@ -110,6 +110,111 @@ func Test_loadProcesses(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with class",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,system
+2,9,3,1, batch`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Class:         "system",
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+					Class:         "batch",
+				},
+			},
+		},
+		{
+			name: "with affinity",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,,3`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Affinity:      3,
+				},
+			},
+		},
+		{
+			name: "with deadline",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,8
+2,9,3,1,,`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Deadline:      8,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+				},
+			},
+		},
+		{
+			name: "with user and group",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,,,alice,staff`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					User:          "alice",
+					Group:         "staff",
+				},
+			},
+		},
+		{
+			name: "with bursts",
+			args: args{
+				r: strings.NewReader(`1,"cpu:5,io:3,cpu:2",0,2`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 7,
+					Bursts:        []Burst{{Duration: 5}, {IO: true, Duration: 3}, {Duration: 2}},
+					Priority:      2,
+				},
+			},
+		},
+		{
+			name: "bursts not alternating",
+			args: args{
+				r: strings.NewReader(`1,"cpu:5,cpu:2",0,2`),
+			},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name: "bursts ending in io",
+			args: args{
+				r: strings.NewReader(`1,"cpu:5,io:3",0,2`),
+			},
+			wantErr: ErrInvalidArgs,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,144 @@
+package main
+
+import "fmt"
+
+func init() {
+	Register("mlfq", "Multilevel feedback queue", func(opts Options) Scheduler {
+		return funcScheduler{name: "mlfq", opts: opts, run: mlfq}
+	})
+}
+
+// mlfq runs processes through a multilevel feedback queue. Every process starts
+// at the top level; one that uses up its level's quantum drops a level, and a
+// process in a higher level always preempts one in a lower level.
+func mlfq(opts Options, processes []Process) Result {
+	tasks, gantt := simulate(processes, func() Policy { return newMLFQPolicy(opts.MLFQ) }, opts)
+	r := newResult(tasks, gantt)
+	r.ShowLevels = true
+
+	levels := make([]string, len(tasks))
+	for i, t := range tasks {
+		levels[i] = fmt.Sprint(t.Level)
+	}
+	r.Columns = append(r.Columns, Column{Header: "Level", Values: levels})
+
+	return r
+}
+
+type mlfqPolicy struct {
+	queues    []readyQueue
+	quanta    []int64
+	boost     int64
+	nextBoost int64
+	granted   map[*Task]mlfqGrant // the CPU time handed out to each running task
+}
+
+type mlfqGrant struct {
+	level     int
+	remaining int64 // Remaining when the task was dispatched
+}
+
+func newMLFQPolicy(opts MLFQOptions) *mlfqPolicy {
+	quanta := opts.Quanta
+	if len(quanta) == 0 {
+		quanta = DefaultOptions().MLFQ.Quanta
+	}
+
+	return &mlfqPolicy{
+		queues:    make([]readyQueue, len(quanta)),
+		quanta:    quanta,
+		boost:     opts.BoostInterval,
+		nextBoost: opts.BoostInterval,
+		granted:   make(map[*Task]mlfqGrant),
+	}
+}
+
+// Ready queues t at its level, first demoting it if it just used up a whole
+// quantum. A task preempted by a higher level keeps its level.
+func (p *mlfqPolicy) Ready(_ int64, t *Task) {
+	if g, ok := p.granted[t]; ok {
+		delete(p.granted, t)
+		q := p.quanta[g.level]
+		if t.Level == g.level && q > 0 && g.remaining-t.Remaining >= q && t.Level < len(p.queues)-1 {
+			t.Level++
+		}
+	}
+	p.queues[t.Level].add(t)
+}
+
+func (p *mlfqPolicy) Next(int64) *Task {
+	for i := range p.queues {
+		if t := p.queues[i].next(); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+func (p *mlfqPolicy) Slice(_ int64, t *Task) int64 {
+	p.granted[t] = mlfqGrant{level: t.Level, remaining: t.Remaining}
+	return p.quanta[t.Level]
+}
+
+// Ahead ranks tasks by their level.
+func (p *mlfqPolicy) Ahead(a, b *Task) bool { return a.Level < b.Level }
+
+func (p *mlfqPolicy) Preempt(_ int64, running *Task) bool {
+	for i := 0; i < running.Level; i++ {
+		if p.queues[i].Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Steal gives up a waiting task from the lowest level that has one that may
+// move. It keeps its level on the other CPU.
+func (p *mlfqPolicy) Steal(_ int64, allowed func(*Task) bool) *Task {
+	for i := len(p.queues) - 1; i >= 0; i-- {
+		if t := p.queues[i].steal(allowed); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// Complete forgets the quantum a task was given when it finishes or blocks.
+// A task that blocks before using up its quantum keeps its level.
+func (p *mlfqPolicy) Complete(_ int64, t *Task) { delete(p.granted, t) }
+
+// Wake asks for the next boost while any process sits below the top level.
+func (p *mlfqPolicy) Wake(now int64) int64 {
+	if p.boost <= 0 {
+		return -1
+	}
+	waiting := false
+	for i := 1; i < len(p.queues); i++ {
+		waiting = waiting || p.queues[i].Len() > 0
+	}
+	for t := range p.granted {
+		waiting = waiting || t.Level > 0
+	}
+	if !waiting {
+		return -1
+	}
+	if p.nextBoost < now {
+		p.nextBoost = (now + p.boost - 1) / p.boost * p.boost
+	}
+	return p.nextBoost
+}
+
+// Timer moves every process back to the top level, behind the processes that
+// were already there.
+func (p *mlfqPolicy) Timer(now int64) {
+	p.nextBoost = now + p.boost
+	for i := 1; i < len(p.queues); i++ {
+		for t := p.queues[i].next(); t != nil; t = p.queues[i].next() {
+			t.Level = 0
+			p.queues[0].add(t)
+		}
+	}
+	for t := range p.granted {
+		t.Level = 0
+	}
+}
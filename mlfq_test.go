@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMLFQ(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	tests := []struct {
+		name       string
+		opts       MLFQOptions
+		wantGantt  []TimeSlice
+		wantLevels []string
+	}{
+		{
+			name: "demotion with an FCFS bottom level",
+			opts: MLFQOptions{Quanta: []int64{2, 4, 0}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2, Level: 0},
+				{PID: 1, Start: 2, Stop: 3, Level: 1},
+				{PID: 2, Start: 3, Stop: 5, Level: 0},
+				{PID: 1, Start: 5, Stop: 6, Level: 1},
+				{PID: 3, Start: 6, Stop: 8, Level: 0},
+				{PID: 2, Start: 8, Stop: 12, Level: 1},
+				{PID: 1, Start: 12, Stop: 13, Level: 1},
+				{PID: 3, Start: 13, Stop: 17, Level: 1},
+				{PID: 2, Start: 17, Stop: 20, Level: 2},
+			},
+			wantLevels: []string{"1", "2", "1"},
+		},
+		{
+			name: "periodic boost",
+			opts: MLFQOptions{Quanta: []int64{2, 4, 0}, BoostInterval: 10},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2, Level: 0},
+				{PID: 1, Start: 2, Stop: 3, Level: 1},
+				{PID: 2, Start: 3, Stop: 5, Level: 0},
+				{PID: 1, Start: 5, Stop: 6, Level: 1},
+				{PID: 3, Start: 6, Stop: 8, Level: 0},
+				{PID: 2, Start: 8, Stop: 12, Level: 1},
+				{PID: 1, Start: 12, Stop: 13, Level: 0},
+				{PID: 3, Start: 13, Stop: 15, Level: 0},
+				{PID: 2, Start: 15, Stop: 17, Level: 0},
+				{PID: 3, Start: 17, Stop: 19, Level: 1},
+				{PID: 2, Start: 19, Stop: 20, Level: 1},
+			},
+			wantLevels: []string{"0", "1", "1"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultOptions()
+			opts.MLFQ = tt.opts
+			got := mlfq(opts, processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(got.Columns[0].Values, tt.wantLevels) {
+				t.Errorf("levels = %v, want %v", got.Columns[0].Values, tt.wantLevels)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("mlq", "Multilevel queue", func(opts Options) Scheduler {
+		return funcScheduler{name: "mlq", opts: opts, run: mlq}
+	})
+}
+
+// mlq runs each process class in its own queue with its own policy, and
+// reports the timings of each class alongside the overall ones.
+func mlq(opts Options, processes []Process) Result {
+	var policies []*mlqPolicy
+	r := newResult(simulate(processes, collect(&policies, func() *mlqPolicy { return newMLQPolicy(opts.MLQ) }), opts))
+	p := policies[0]
+	r.Classes = groupStats(r, func(ps ProcessStats) string { return p.classes[p.queueOf(ps.Class)].Name })
+
+	return r
+}
+
+type mlqPolicy struct {
+	classes []ClassQueue
+	queues  []*orderedPolicy
+	index   map[string]int
+
+	turns   []int64 // length of each queue's turn, or nil for fixed priority
+	turn    int     // queue whose turn it is, or -1 before the first turn
+	turnEnd int64
+	running []*Task
+}
+
+func newMLQPolicy(opts MLQOptions) *mlqPolicy {
+	classes := opts.Classes
+	if len(classes) == 0 {
+		classes = DefaultOptions().MLQ.Classes
+	}
+	p := &mlqPolicy{
+		classes: classes,
+		queues:  make([]*orderedPolicy, len(classes)),
+		index:   make(map[string]int, len(classes)),
+	}
+	for i, c := range classes {
+		p.index[c.Name] = i
+		switch c.Policy {
+		case "rr":
+			quantum := c.Quantum
+			if quantum <= 0 {
+				quantum = 1
+			}
+			p.queues[i] = newOrderedPolicy(nil, quantum, false)
+		case "sjf":
+			p.queues[i] = newOrderedPolicy(shorterBurst, 0, false)
+		default:
+			p.queues[i] = newOrderedPolicy(nil, 0, false)
+		}
+	}
+
+	if len(opts.Shares) == len(classes) {
+		var total int64
+		for _, s := range opts.Shares {
+			total += s
+		}
+		p.turns = make([]int64, len(classes))
+		p.turn = -1
+		for i, s := range opts.Shares {
+			p.turns[i] = opts.Cycle * s / total
+			if p.turns[i] < 1 {
+				p.turns[i] = 1
+			}
+		}
+	}
+
+	return p
+}
+
+func (p *mlqPolicy) queueOf(class string) int {
+	if i, ok := p.index[class]; ok {
+		return i
+	}
+	return len(p.queues) - 1
+}
+
+func (p *mlqPolicy) Ready(now int64, t *Task) {
+	if i := indexOf(p.running, t); i >= 0 {
+		p.running = append(p.running[:i], p.running[i+1:]...)
+	}
+	p.queues[p.queueOf(t.Class)].Ready(now, t)
+}
+
+func (p *mlqPolicy) Next(now int64) *Task {
+	if p.turns != nil {
+		if p.turn < 0 || now >= p.turnEnd || p.queues[p.turn].queue.Len() == 0 {
+			p.rotate(now)
+		}
+		if p.turn < 0 {
+			return nil
+		}
+		t := p.queues[p.turn].Next(now)
+		if t != nil {
+			p.running = append(p.running, t)
+		}
+		return t
+	}
+	for _, q := range p.queues {
+		if t := q.Next(now); t != nil {
+			p.running = append(p.running, t)
+			return t
+		}
+	}
+	return nil
+}
+
+func (p *mlqPolicy) Slice(now int64, t *Task) int64 {
+	return p.queues[p.queueOf(t.Class)].Slice(now, t)
+}
+
+// Ahead ranks tasks by class under fixed priority, or puts the class whose
+// turn it is first when time-slicing.
+func (p *mlqPolicy) Ahead(a, b *Task) bool {
+	qa, qb := p.queueOf(a.Class), p.queueOf(b.Class)
+	if p.turns != nil {
+		return qa == p.turn && qb != p.turn
+	}
+	return qa < qb
+}
+
+// Preempt gives the CPU to a higher class under fixed priority, or to the
+// queue whose turn it is when time-slicing.
+func (p *mlqPolicy) Preempt(_ int64, running *Task) bool {
+	q := p.queueOf(running.Class)
+	if p.turns != nil {
+		return p.turn >= 0 && q != p.turn && p.queues[p.turn].queue.Len() > 0
+	}
+	for i := 0; i < q; i++ {
+		if p.queues[i].queue.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Steal gives up a waiting task from the lowest class that has one that may
+// move.
+func (p *mlqPolicy) Steal(now int64, allowed func(*Task) bool) *Task {
+	for i := len(p.queues) - 1; i >= 0; i-- {
+		if t := p.queues[i].Steal(now, allowed); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+func (p *mlqPolicy) Complete(_ int64, t *Task) {
+	if i := indexOf(p.running, t); i >= 0 {
+		p.running = append(p.running[:i], p.running[i+1:]...)
+	}
+}
+
+// Wake asks for the end of the current turn while there is work to do.
+func (p *mlqPolicy) Wake(int64) int64 {
+	if p.turns == nil {
+		return -1
+	}
+	if len(p.running) > 0 {
+		return p.turnEnd
+	}
+	for _, q := range p.queues {
+		if q.queue.Len() > 0 {
+			return p.turnEnd
+		}
+	}
+	return -1
+}
+
+func (p *mlqPolicy) Timer(now int64) { p.rotate(now) }
+
+// rotate hands the turn to the next queue that has work, starting from the
+// first queue before any turn, or back to the first running task's queue if no
+// other queue does. With no work at all, no turn starts.
+func (p *mlqPolicy) rotate(now int64) {
+	for k := 1; k <= len(p.queues); k++ {
+		if i := (p.turn + k) % len(p.queues); p.queues[i].queue.Len() > 0 {
+			p.turn = i
+			p.turnEnd = now + p.turns[i]
+			return
+		}
+	}
+	if len(p.running) > 0 {
+		p.turn = p.queueOf(p.running[0].Class)
+	}
+	if p.turn < 0 {
+		return
+	}
+	p.turnEnd = now + p.turns[p.turn]
+}
+
+// parseClassQueues parses a list of class queues such as
+// "system=fcfs,interactive=rr:2,batch=sjf".
+func parseClassQueues(s string) ([]ClassQueue, error) {
+	var classes []ClassQueue
+	for _, field := range strings.Split(s, ",") {
+		name, spec, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: class %q must be name=policy", ErrInvalidArgs, field)
+		}
+		c := ClassQueue{Name: name}
+		policy, quantum, hasQuantum := strings.Cut(spec, ":")
+		c.Policy = policy
+		switch policy {
+		case "fcfs", "sjf":
+			if hasQuantum {
+				return nil, fmt.Errorf("%w: class %q: only rr takes a quantum", ErrInvalidArgs, name)
+			}
+		case "rr":
+			c.Quantum = 1
+			if hasQuantum {
+				q, err := strconv.ParseInt(quantum, 10, 64)
+				if err != nil || q <= 0 {
+					return nil, fmt.Errorf("%w: class %q: bad quantum %q", ErrInvalidArgs, name, quantum)
+				}
+				c.Quantum = q
+			}
+		default:
+			return nil, fmt.Errorf("%w: class %q: unknown policy %q", ErrInvalidArgs, name, policy)
+		}
+		classes = append(classes, c)
+	}
+
+	return classes, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMLQ(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Class: "batch"},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 9, Class: "interactive"},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 3, Class: "system"},
+		{ProcessID: 4, ArrivalTime: 3, BurstDuration: 4, Class: "interactive"},
+	}
+	tests := []struct {
+		name        string
+		shares      []int64
+		wantGantt   []TimeSlice
+		wantClasses []GroupStats
+	}{
+		{
+			name: "fixed priority",
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 2},
+				{PID: 3, Start: 2, Stop: 5},
+				{PID: 2, Start: 5, Stop: 7},
+				{PID: 4, Start: 7, Stop: 9},
+				{PID: 2, Start: 9, Stop: 11},
+				{PID: 4, Start: 11, Stop: 13},
+				{PID: 2, Start: 13, Stop: 17},
+				{PID: 1, Start: 17, Stop: 21},
+			},
+			wantClasses: []GroupStats{
+				{Name: "batch", Processes: 1, CPUTime: 5, AveWait: 16, AveTurnaround: 21, AveResponse: 0},
+				{Name: "interactive", Processes: 2, CPUTime: 13, AveWait: 6.5, AveTurnaround: 13, AveResponse: 2},
+				{Name: "system", Processes: 1, CPUTime: 3, AveWait: 0, AveTurnaround: 3, AveResponse: 0},
+			},
+		},
+		{
+			name:   "time-sliced between queues",
+			shares: []int64{5, 3, 2},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 3, Start: 2, Stop: 5},
+				{PID: 2, Start: 5, Stop: 7},
+				{PID: 4, Start: 7, Stop: 8},
+				{PID: 1, Start: 8, Stop: 10},
+				{PID: 2, Start: 10, Stop: 12},
+				{PID: 4, Start: 12, Stop: 13},
+				{PID: 1, Start: 13, Stop: 14},
+				{PID: 2, Start: 14, Stop: 16},
+				{PID: 4, Start: 16, Stop: 18},
+				{PID: 2, Start: 18, Stop: 21},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultOptions()
+			opts.MLQ.Shares = tt.shares
+			got := mlq(opts, processes)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if tt.wantClasses != nil && !reflect.DeepEqual(got.Classes, tt.wantClasses) {
+				t.Errorf("Classes = %v, want %v", got.Classes, tt.wantClasses)
+			}
+		})
+	}
+}
+
+func TestMLQFirstTurn(t *testing.T) {
+	t.Parallel()
+	// Both classes are ready at 0, so the first turn goes to the first one.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Class: "batch"},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 4, Class: "system"},
+	}
+	opts := DefaultOptions()
+	opts.MLQ.Classes = []ClassQueue{{Name: "system", Policy: "fcfs"}, {Name: "batch", Policy: "fcfs"}}
+	opts.MLQ.Shares = []int64{80, 20}
+	want := []TimeSlice{
+		{PID: 2, Start: 0, Stop: 4},
+		{PID: 1, Start: 4, Stop: 8},
+	}
+	if got := mlq(opts, processes).Gantt; !reflect.DeepEqual(got, want) {
+		t.Errorf("Gantt = %v, want %v", got, want)
+	}
+}
+
+func Test_parseClassQueues(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    []ClassQueue
+		wantErr error
+	}{
+		{
+			name: "success",
+			s:    "system=fcfs, interactive=rr:4,batch=sjf,idle=rr",
+			want: []ClassQueue{
+				{Name: "system", Policy: "fcfs"},
+				{Name: "interactive", Policy: "rr", Quantum: 4},
+				{Name: "batch", Policy: "sjf"},
+				{Name: "idle", Policy: "rr", Quantum: 1},
+			},
+		},
+		{name: "missing policy", s: "system", wantErr: ErrInvalidArgs},
+		{name: "unknown policy", s: "system=lifo", wantErr: ErrInvalidArgs},
+		{name: "quantum on fcfs", s: "system=fcfs:2", wantErr: ErrInvalidArgs},
+		{name: "bad quantum", s: "system=rr:0", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseClassQueues(tt.s)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseClassQueues() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
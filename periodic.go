@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+)
+
+func init() {
+	Register("rm", "Rate monotonic", func(opts Options) Scheduler {
+		return funcScheduler{name: "rm", opts: opts, run: rm}
+	})
+	Register("dm", "Deadline monotonic", func(opts Options) Scheduler {
+		return funcScheduler{name: "dm", opts: opts, run: dm}
+	})
+}
+
+// PeriodicTask is a real-time task that releases a job every Period, starting
+// at Phase. Each job needs WCET units of CPU and must finish within Deadline
+// of its release.
+type PeriodicTask struct {
+	TaskID int64
+	Period int64
+	WCET   int64
+	// Deadline is relative to each job's release, and defaults to Period.
+	Deadline int64
+	Phase    int64
+}
+
+// RelativeDeadline is the task's deadline, or its period if it has none.
+func (t PeriodicTask) RelativeDeadline() int64 {
+	if t.Deadline > 0 {
+		return t.Deadline
+	}
+	return t.Period
+}
+
+// loadPeriodicTasks reads periodic tasks as id,period,wcet[,deadline[,phase]].
+func loadPeriodicTasks(r io.Reader) ([]PeriodicTask, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading CSV", err)
+	}
+
+	tasks := make([]PeriodicTask, len(rows))
+	for i := range rows {
+		if len(rows[i]) < 3 {
+			return nil, fmt.Errorf("%w: periodic task on line %d needs id,period,wcet", ErrInvalidArgs, i+1)
+		}
+		tasks[i].TaskID = mustStrToInt(rows[i][0])
+		tasks[i].Period = mustStrToInt(rows[i][1])
+		tasks[i].WCET = mustStrToInt(rows[i][2])
+		if len(rows[i]) >= 4 {
+			tasks[i].Deadline = optStrToInt(rows[i][3])
+		}
+		if len(rows[i]) >= 5 {
+			tasks[i].Phase = optStrToInt(rows[i][4])
+		}
+		if tasks[i].Period <= 0 || tasks[i].WCET <= 0 || tasks[i].Deadline < 0 || tasks[i].Phase < 0 {
+			return nil, fmt.Errorf("%w: periodic task %d needs a positive period and WCET", ErrInvalidArgs, tasks[i].TaskID)
+		}
+	}
+
+	return tasks, nil
+}
+
+// hyperperiod is the least common multiple of the tasks' periods, after which
+// the pattern of releases repeats.
+func hyperperiod(tasks []PeriodicTask) int64 {
+	h := int64(1)
+	for _, t := range tasks {
+		a, b := h, t.Period
+		for b != 0 {
+			a, b = b, a%b
+		}
+		h = h / a * t.Period
+	}
+	return h
+}
+
+// expandJobs turns periodic tasks into the jobs they release from time 0 until
+// one hyperperiod after the latest phase, in release order.
+func expandJobs(tasks []PeriodicTask) []Process {
+	var latest int64
+	for _, t := range tasks {
+		if t.Phase > latest {
+			latest = t.Phase
+		}
+	}
+	horizon := latest + hyperperiod(tasks)
+
+	var jobs []Process
+	for _, t := range tasks {
+		for k, release := int64(1), t.Phase; release < horizon; k, release = k+1, release+t.Period {
+			jobs = append(jobs, Process{
+				ProcessID:     t.TaskID,
+				ArrivalTime:   release,
+				BurstDuration: t.WCET,
+				Deadline:      release + t.RelativeDeadline(),
+				Period:        t.Period,
+				Job:           k,
+			})
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].ArrivalTime < jobs[j].ArrivalTime })
+
+	return jobs
+}
+
+// rm is fixed-priority scheduling with the shortest period most urgent.
+// Processes that are not periodic jobs run only when no job is ready.
+func rm(opts Options, processes []Process) Result {
+	return newResult(simulate(processes, ordered(shorterPeriod, 0, true), opts))
+}
+
+// dm is fixed-priority scheduling with the shortest relative deadline most
+// urgent, which is the same as rm when every deadline equals its period.
+func dm(opts Options, processes []Process) Result {
+	return newResult(simulate(processes, ordered(shorterRelativeDeadline, 0, true), opts))
+}
+
+func shorterPeriod(a, b *Task) bool {
+	switch {
+	case a.Period <= 0:
+		return false
+	case b.Period <= 0:
+		return true
+	}
+	return a.Period < b.Period
+}
+
+func shorterRelativeDeadline(a, b *Task) bool {
+	switch {
+	case a.Deadline <= 0:
+		return false
+	case b.Deadline <= 0:
+		return true
+	}
+	return a.Deadline-a.ArrivalTime < b.Deadline-b.ArrivalTime
+}
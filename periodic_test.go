@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandJobs(t *testing.T) {
+	t.Parallel()
+	tasks, err := loadPeriodicTasks(strings.NewReader("1,4,1,,\n2,6,2,5,2\n"))
+	if err != nil {
+		t.Fatalf("loadPeriodicTasks() error = %v", err)
+	}
+	if h := hyperperiod(tasks); h != 12 {
+		t.Errorf("hyperperiod() = %d, want 12", h)
+	}
+	want := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 1, Deadline: 4, Period: 4, Job: 1},
+		{ProcessID: 2, ArrivalTime: 2, BurstDuration: 2, Deadline: 7, Period: 6, Job: 1},
+		{ProcessID: 1, ArrivalTime: 4, BurstDuration: 1, Deadline: 8, Period: 4, Job: 2},
+		{ProcessID: 1, ArrivalTime: 8, BurstDuration: 1, Deadline: 12, Period: 4, Job: 3},
+		{ProcessID: 2, ArrivalTime: 8, BurstDuration: 2, Deadline: 13, Period: 6, Job: 2},
+		{ProcessID: 1, ArrivalTime: 12, BurstDuration: 1, Deadline: 16, Period: 4, Job: 4},
+	}
+	if got := expandJobs(tasks); !reflect.DeepEqual(got, want) {
+		t.Errorf("expandJobs() = %v, want %v", got, want)
+	}
+}
+
+func TestMonotonicSchedulers(t *testing.T) {
+	t.Parallel()
+	// Task 2 has the longer period but the tighter deadline, so only
+	// deadline monotonic meets it.
+	jobs := expandJobs([]PeriodicTask{
+		{TaskID: 1, Period: 5, WCET: 2},
+		{TaskID: 2, Period: 10, WCET: 3, Deadline: 3},
+	})
+	tests := []struct {
+		name      string
+		run       func(Options, []Process) Result
+		wantGantt []TimeSlice
+		wantMet   []string
+	}{
+		{
+			name: "rm",
+			run:  rm,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2, Job: 1},
+				{PID: 2, Start: 2, Stop: 5, Job: 1},
+				{PID: 1, Start: 5, Stop: 7, Job: 2},
+			},
+			wantMet: []string{"yes", "no", "yes"},
+		},
+		{
+			name: "dm",
+			run:  dm,
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 3, Job: 1},
+				{PID: 1, Start: 3, Stop: 5, Job: 1},
+				{PID: 1, Start: 5, Stop: 7, Job: 2},
+			},
+			wantMet: []string{"yes", "yes", "yes"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.run(DefaultOptions(), jobs)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if met := got.Columns[2].Values; !reflect.DeepEqual(met, tt.wantMet) {
+				t.Errorf("Met? = %v, want %v", met, tt.wantMet)
+			}
+		})
+	}
+}
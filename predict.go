@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+func init() {
+	Register("sjf-predicted", "Shortest-job-first, predicted bursts", func(opts Options) Scheduler {
+		return funcScheduler{name: "sjf-predicted", opts: opts, run: sjfPredicted}
+	})
+	Register("srtf-predicted", "Shortest-remaining-time-first, predicted bursts", func(opts Options) Scheduler {
+		return funcScheduler{name: "srtf-predicted", opts: opts, run: srtfPredicted}
+	})
+}
+
+// sjfPredicted is sjf without knowing burst lengths in advance: it runs the
+// process whose next CPU burst is predicted to be shortest, by exponential
+// averaging of its past bursts. The table compares each process's prediction
+// error and wait with its wait under sjf.
+func sjfPredicted(opts Options, processes []Process) Result {
+	return predicted(opts, processes, false, sjf)
+}
+
+// srtfPredicted is srtf with predicted bursts: a newly ready process preempts
+// the running one if its predicted burst is shorter than what the running one
+// is predicted to have left.
+func srtfPredicted(opts Options, processes []Process) Result {
+	return predicted(opts, processes, true, srtf)
+}
+
+func predicted(opts Options, processes []Process, preemptive bool, clairvoyant func(Options, []Process) Result) Result {
+	pr := newPredictor(opts.Prediction)
+	tasks, gantt := simulate(processes, func() Policy { return newPredictPolicy(pr, preemptive) }, opts)
+	r := newResult(tasks, gantt)
+	known := clairvoyant(opts, processes)
+
+	errs := make([]string, len(tasks))
+	waits := make([]string, len(tasks))
+	var total float64
+	var bursts int
+	for i, t := range tasks {
+		errs[i] = fmt.Sprintf("%.2f", pr.errors[t]/float64(pr.bursts[t]))
+		total += pr.errors[t]
+		bursts += pr.bursts[t]
+		waits[i] = fmt.Sprint(known.Processes[i].Wait)
+	}
+	r.Columns = append(r.Columns,
+		Column{Header: "Mean error", Values: errs, Footer: fmt.Sprintf("Average\n%.2f", total/float64(bursts))},
+		Column{Header: "Clairvoyant wait", Values: waits, Footer: fmt.Sprintf("Average\n%.2f", known.AveWait)},
+	)
+
+	return r
+}
+
+// predictor predicts each task's next CPU burst as tau_{n+1} = alpha * t_n +
+// (1 - alpha) * tau_n, where t_n is the length of the burst just finished and
+// tau_0 is the Tau option. It is shared by the policies of every CPU, so a
+// task keeps its history when it moves.
+type predictor struct {
+	tau0   float64
+	alpha  float64
+	tau    map[*Task]float64
+	errors map[*Task]float64 // total absolute error of each task's predictions
+	bursts map[*Task]int     // CPU bursts each task has finished
+}
+
+func newPredictor(opts PredictionOptions) *predictor {
+	pr := &predictor{
+		tau0:   opts.Tau,
+		alpha:  opts.Alpha,
+		tau:    make(map[*Task]float64),
+		errors: make(map[*Task]float64),
+		bursts: make(map[*Task]int),
+	}
+	if pr.tau0 <= 0 {
+		pr.tau0 = DefaultOptions().Prediction.Tau
+	}
+
+	return pr
+}
+
+// left is how much of its current burst t is predicted to still need.
+func (pr *predictor) left(t *Task) float64 {
+	tau, ok := pr.tau[t]
+	if !ok {
+		tau = pr.tau0
+	}
+	return math.Max(tau-float64(t.burst()-t.Remaining), 0)
+}
+
+// predictPolicy orders tasks by what is predicted to be left of their current
+// CPU burst, which for a task that has not run yet is its whole next burst.
+type predictPolicy struct {
+	*orderedPolicy
+	predictor *predictor
+}
+
+func newPredictPolicy(pr *predictor, preemptive bool) *predictPolicy {
+	return &predictPolicy{
+		orderedPolicy: newOrderedPolicy(func(a, b *Task) bool { return pr.left(a) < pr.left(b) }, 0, preemptive),
+		predictor:     pr,
+	}
+}
+
+// Complete scores the prediction for the burst t has just finished and makes
+// the next one.
+func (p *predictPolicy) Complete(_ int64, t *Task) {
+	pr := p.predictor
+	tau, ok := pr.tau[t]
+	if !ok {
+		tau = pr.tau0
+	}
+	actual := float64(t.burst())
+	pr.errors[t] += math.Abs(tau - actual)
+	pr.bursts[t]++
+	pr.tau[t] = pr.alpha*actual + (1-pr.alpha)*tau
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSJFPredicted(t *testing.T) {
+	t.Parallel()
+	// Process 1's short first burst brings its prediction down from 5 to 3.5,
+	// so at time 6 it goes ahead of process 3, which is predicted to need 5
+	// but only needs 1.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Bursts: []Burst{{Duration: 2}, {IO: true, Duration: 1}, {Duration: 2}}},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 1},
+	}
+	got := sjfPredicted(DefaultOptions(), processes)
+
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2},
+		{PID: 2, Start: 2, Stop: 6},
+		{PID: 1, Start: 6, Stop: 8},
+		{PID: 3, Start: 8, Stop: 9},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	columns := map[string][]string{}
+	for _, c := range got.Columns {
+		columns[c.Header] = c.Values
+	}
+	if want := []string{"2.25", "1.00", "4.00"}; !reflect.DeepEqual(columns["Mean error"], want) {
+		t.Errorf("Mean error = %v, want %v", columns["Mean error"], want)
+	}
+	if want := []string{"4", "2", "3"}; !reflect.DeepEqual(columns["Clairvoyant wait"], want) {
+		t.Errorf("Clairvoyant wait = %v, want %v", columns["Clairvoyant wait"], want)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//region Output helpers
+
+// Render writes a Result as a titled GANTT chart followed by a table of timings.
+func Render(w io.Writer, title string, r Result) {
+	outputTitle(w, title)
+	outputGantt(w, r)
+	outputSchedule(w, r)
+	outputGroups(w, "Class summary", "Class", r.Classes)
+	outputGroups(w, "Group summary", "Group", r.Groups)
+	outputSummary(w, r)
+	outputCPUs(w, r.CPUs)
+	outputDevices(w, r.Devices)
+	outputLog(w, r.Log)
+}
+
+func outputTitle(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+}
+
+// outputGantt prints the chart, with a row for each CPU when there are several
+// and one for each I/O device.
+func outputGantt(w io.Writer, r Result) {
+	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	if len(r.CPUs) == 0 {
+		outputGanttRows(w, r.Gantt, r.ShowLevels)
+	}
+	for _, c := range r.CPUs {
+		var gantt []TimeSlice
+		for _, s := range r.Gantt {
+			if s.CPU == c.CPU {
+				gantt = append(gantt, s)
+			}
+		}
+		_, _ = fmt.Fprintf(w, "CPU %d\n", c.CPU)
+		outputGanttRows(w, gantt, r.ShowLevels)
+	}
+	for _, d := range r.Devices {
+		_, _ = fmt.Fprintf(w, "Device %s\n", d.Name)
+		outputGanttRows(w, d.Gantt, false)
+	}
+}
+
+func outputGanttRows(w io.Writer, gantt []TimeSlice, levels bool) {
+	widths := make([]int, len(gantt))
+	for i := range gantt {
+		widths[i] = len(ganttLabel(gantt[i]))
+		if levels && len(levelLabel(gantt[i])) > widths[i] {
+			widths[i] = len(levelLabel(gantt[i]))
+		}
+	}
+	outputGanttRow(w, gantt, widths, ganttLabel)
+	if levels {
+		outputGanttRow(w, gantt, widths, levelLabel)
+	}
+	for i := range gantt {
+		_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Start), "\t")
+		if len(gantt)-1 == i {
+			_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Stop))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\n\n")
+}
+
+func outputSchedule(w io.Writer, r Result) {
+	_, _ = fmt.Fprintln(w, "Schedule table")
+	table := tablewriter.NewWriter(w)
+	header := []string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"}
+	footer := []string{"", "", "", "",
+		fmt.Sprintf("Average\n%.2f", r.AveWait),
+		fmt.Sprintf("Average\n%.2f", r.AveTurnaround),
+		fmt.Sprintf("Throughput\n%.2f/t", r.Throughput)}
+	for _, c := range r.Columns {
+		header = append(header, c.Header)
+		footer = append(footer, c.Footer)
+	}
+	table.SetHeader(header)
+	table.AppendBulk(scheduleRows(r))
+	table.SetFooter(footer)
+	table.Render()
+}
+
+// outputGroups prints a table of groups, with their entitled and actual
+// shares of the CPU when they have them.
+func outputGroups(w io.Writer, title, name string, groups []GroupStats) {
+	if len(groups) == 0 {
+		return
+	}
+	shares := false
+	for _, g := range groups {
+		shares = shares || g.Entitlement > 0
+	}
+	_, _ = fmt.Fprintln(w, title)
+	table := tablewriter.NewWriter(w)
+	header := []string{name, "Processes", "CPU time", "Ave wait", "Ave turnaround", "Ave response"}
+	if shares {
+		header = append(header, "Entitled", "Share")
+	}
+	table.SetHeader(header)
+	for _, g := range groups {
+		row := []string{
+			g.Name,
+			fmt.Sprint(g.Processes),
+			fmt.Sprint(g.CPUTime),
+			fmt.Sprintf("%.2f", g.AveWait),
+			fmt.Sprintf("%.2f", g.AveTurnaround),
+			fmt.Sprintf("%.2f", g.AveResponse),
+		}
+		if shares {
+			row = append(row, fmt.Sprintf("%.1f%%", g.Entitlement*100), fmt.Sprintf("%.1f%%", g.Share*100))
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
+
+// outputSummary reports CPU utilization when some of the time was not spent
+// running processes. A CPU that was busy the whole time has nothing to report.
+func outputSummary(w io.Writer, r Result) {
+	if r.Utilization >= 1 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "CPU utilization: %.2f%% (%d context switches, %d idle)\n", r.Utilization*100, r.ContextSwitches, r.Idle)
+}
+
+func outputCPUs(w io.Writer, cpus []CPUStats) {
+	if len(cpus) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "CPU summary")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"CPU", "Busy", "Switching", "Migrating", "Idle", "Utilization"})
+	for _, c := range cpus {
+		table.Append([]string{
+			fmt.Sprint(c.CPU),
+			fmt.Sprint(c.Busy),
+			fmt.Sprint(c.Switching),
+			fmt.Sprint(c.Migrating),
+			fmt.Sprint(c.Idle),
+			fmt.Sprintf("%.2f%%", c.Utilization*100),
+		})
+	}
+	table.Render()
+}
+
+func outputDevices(w io.Writer, devices []DeviceStats) {
+	if len(devices) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Device summary")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Device", "Requests", "Busy", "Utilization"})
+	for _, d := range devices {
+		table.Append([]string{
+			d.Name,
+			fmt.Sprint(d.Requests),
+			fmt.Sprint(d.Busy),
+			fmt.Sprintf("%.2f%%", d.Utilization*100),
+		})
+	}
+	table.Render()
+}
+
+func outputLog(w io.Writer, log []string) {
+	if len(log) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Decisions")
+	for _, line := range log {
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
+
+// outputGanttRow prints one cell per slice, with each label padded to the
+// width of its column.
+func outputGanttRow(w io.Writer, gantt []TimeSlice, widths []int, label func(TimeSlice) string) {
+	_, _ = fmt.Fprint(w, "|")
+	for i := range gantt {
+		text := label(gantt[i])
+		text += strings.Repeat(" ", widths[i]-len(text))
+		padding := strings.Repeat(" ", (8-widths[i])/2)
+		_, _ = fmt.Fprint(w, padding, text, padding, "|")
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+func ganttLabel(s TimeSlice) string {
+	switch s.PID {
+	case IdlePID:
+		return "idle"
+	case DispatcherPID:
+		return "cs"
+	case MigrationPID:
+		return "mig"
+	}
+	return jobLabel(s.PID, s.Job)
+}
+
+// jobLabel names a process, or job j of periodic task pid as "pid.j".
+func jobLabel(pid, job int64) string {
+	if job > 0 {
+		return fmt.Sprintf("%d.%d", pid, job)
+	}
+	return fmt.Sprint(pid)
+}
+
+func levelLabel(s TimeSlice) string {
+	if s.PID < 0 {
+		return ""
+	}
+	return fmt.Sprint("L", s.Level)
+}
+
+func scheduleRows(r Result) [][]string {
+	rows := make([][]string, len(r.Processes))
+	for i, p := range r.Processes {
+		rows[i] = []string{
+			jobLabel(p.ProcessID, p.Job),
+			fmt.Sprint(p.Priority),
+			fmt.Sprint(p.BurstDuration),
+			fmt.Sprint(p.ArrivalTime),
+			fmt.Sprint(p.Wait),
+			fmt.Sprint(p.Turnaround),
+			fmt.Sprint(p.Completion),
+		}
+		for _, c := range r.Columns {
+			rows[i] = append(rows[i], c.Values[i])
+		}
+	}
+
+	return rows
+}
+
+//endregion
@@ -0,0 +1,700 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Scheduler is a CPU scheduling policy that can be run over a set of processes.
+type Scheduler interface {
+	// Name is the short, unique name the scheduler is registered under.
+	Name() string
+	// Options returns the options the scheduler was built with.
+	Options() Options
+	// Run schedules the processes and returns the resulting schedule.
+	Run(processes []Process) Result
+}
+
+// Options holds the tunable parameters shared by the schedulers. Each scheduler
+// reads only the fields that apply to it; zero values select the defaults.
+type Options struct {
+	// Quantum is the time slice given to a process before it is preempted by
+	// time-sliced schedulers such as round-robin. It must be at least 1.
+	Quantum int64
+	// ContextSwitch is the time it takes to switch the CPU from one process
+	// to another. It is spent in the dispatcher and counts against utilization.
+	ContextSwitch int64
+	// HighPriorityFirst makes priority schedulers treat larger Priority values
+	// as more urgent. By default lower numbers win.
+	HighPriorityFirst bool
+	// AgingInterval, when positive, makes priority schedulers improve the
+	// effective priority of a waiting process by AgingStep every AgingInterval
+	// time units it spends in the ready queue.
+	AgingInterval int64
+	AgingStep     int64
+	// Seed seeds the random source of randomised schedulers such as lottery,
+	// so that runs can be reproduced.
+	Seed int64
+	// MLFQ configures the multilevel feedback queue scheduler.
+	MLFQ MLFQOptions
+	// MLQ configures the multilevel queue scheduler.
+	MLQ MLQOptions
+	// CFS configures the completely fair scheduler.
+	CFS CFSOptions
+	// EEVDF configures the earliest eligible virtual deadline first scheduler.
+	EEVDF EEVDFOptions
+	// Verbose asks schedulers that support it to log how they made each
+	// decision.
+	Verbose bool
+	// CPUs is the number of processors to schedule onto.
+	CPUs int
+	// RunQueue is RunQueueGlobal for one run queue that every CPU takes work
+	// from, or RunQueuePerCPU for a run queue per CPU, with each process
+	// placed on the least loaded one its affinity allows when it arrives.
+	// A global run queue cannot honour affinity, so processes restricted to
+	// some of several CPUs need per-CPU run queues.
+	RunQueue string
+	// Balance configures the load balancer for per-CPU run queues.
+	Balance BalanceOptions
+	// FairShare configures the fair share scheduler.
+	FairShare FairShareOptions
+	// Prediction configures the schedulers that predict CPU bursts rather
+	// than knowing them in advance.
+	Prediction PredictionOptions
+	// Devices lists the I/O devices that I/O bursts naming them queue for,
+	// each serving one burst at a time. Bursts for devices not listed here
+	// block for their duration without contending for anything.
+	Devices []Device
+}
+
+// FairShareOptions configures the fair share scheduler.
+type FairShareOptions struct {
+	// Shares is the share of the CPU each group is entitled to, relative to
+	// the others. Groups that are not listed get a share of 1.
+	Shares map[string]int64
+}
+
+// PredictionOptions configures exponential averaging of CPU bursts, which
+// predicts each burst from the ones before it.
+type PredictionOptions struct {
+	// Tau is the prediction for a process's first burst.
+	Tau float64
+	// Alpha is the weight of the latest burst against the previous
+	// prediction, from 0 to 1.
+	Alpha float64
+}
+
+// Device is an I/O device that processes queue for.
+type Device struct {
+	Name string
+	// Policy orders the device queue: "fcfs", "sjf" for the shortest I/O
+	// burst first, or "priority".
+	Policy string
+}
+
+// BalanceOptions configures the load balancer, which moves waiting processes
+// from busy per-CPU run queues to idle ones.
+type BalanceOptions struct {
+	// Interval, when positive, runs the balancer every Interval time units.
+	Interval int64
+	// MigrationCost is the time a process loses warming up a CPU it has been
+	// moved to, charged before it first runs there.
+	MigrationCost int64
+}
+
+// Run queue layouts for the RunQueue option.
+const (
+	RunQueueGlobal = "global"
+	RunQueuePerCPU = "per-cpu"
+)
+
+// MLFQOptions configures the multilevel feedback queue scheduler.
+type MLFQOptions struct {
+	// Quanta holds the quantum of each level, top level first, and so also
+	// sets the number of levels. A quantum of 0 runs that level FCFS, which
+	// only makes sense for the bottom level.
+	Quanta []int64
+	// BoostInterval, when positive, moves every process back to the top
+	// level every BoostInterval time units so that long jobs cannot starve.
+	BoostInterval int64
+}
+
+// DefaultOptions returns the options used when nothing is overridden.
+func DefaultOptions() Options {
+	return Options{
+		Quantum:   1,
+		AgingStep: 1,
+		Seed:      1,
+		CPUs:      1,
+		RunQueue:  RunQueueGlobal,
+		MLFQ: MLFQOptions{
+			Quanta: []int64{2, 4, 0},
+		},
+		MLQ: MLQOptions{
+			Classes: []ClassQueue{
+				{Name: "system", Policy: "fcfs"},
+				{Name: "interactive", Policy: "rr", Quantum: 2},
+				{Name: "batch", Policy: "sjf"},
+			},
+			Cycle: 10,
+		},
+		CFS: CFSOptions{
+			Latency:        6,
+			MinGranularity: 1,
+		},
+		EEVDF: EEVDFOptions{
+			Slice: 3,
+		},
+		Prediction: PredictionOptions{
+			Tau:   5,
+			Alpha: 0.5,
+		},
+	}
+}
+
+// MLQOptions configures the multilevel queue scheduler.
+type MLQOptions struct {
+	// Classes lists one queue per process class, highest priority first.
+	// Processes whose class is not listed join the last queue.
+	Classes []ClassQueue
+	// Shares, when set, time-slices the CPU between the queues instead of
+	// always serving the highest non-empty one. Each queue gets a turn of
+	// Cycle * share / sum(Shares) time units.
+	Shares []int64
+	// Cycle is the length of one round of turns when time-slicing.
+	Cycle int64
+}
+
+// ClassQueue is the queue for one process class.
+type ClassQueue struct {
+	Name string
+	// Policy orders the queue: "fcfs", "sjf" or "rr".
+	Policy string
+	// Quantum is the time slice for an "rr" queue.
+	Quantum int64
+}
+
+// CFSOptions configures the completely fair scheduler, after the kernel's
+// sched_latency and sched_min_granularity tunables.
+type CFSOptions struct {
+	// Latency is the period within which every runnable process should get
+	// to run once.
+	Latency int64
+	// MinGranularity is the shortest timeslice a process is given; with many
+	// processes the period stretches rather than going below it.
+	MinGranularity int64
+}
+
+// EEVDFOptions configures the earliest eligible virtual deadline first scheduler.
+type EEVDFOptions struct {
+	// Slice is the default size of the CPU requests a process makes.
+	Slice int64
+	// Requests overrides the request size of individual processes, by PID.
+	Requests map[int64]int64
+}
+
+// Validate reports option combinations that cannot be run.
+func (o Options) Validate() error {
+	if o.Quantum < 1 {
+		return fmt.Errorf("%w: quantum must be at least 1, got %d", ErrInvalidArgs, o.Quantum)
+	}
+	if o.ContextSwitch < 0 {
+		return fmt.Errorf("%w: context switch cost must not be negative, got %d", ErrInvalidArgs, o.ContextSwitch)
+	}
+	if n := len(o.MLQ.Shares); n > 0 && n != len(o.MLQ.Classes) {
+		return fmt.Errorf("%w: %d multilevel queue shares for %d classes", ErrInvalidArgs, n, len(o.MLQ.Classes))
+	}
+	for _, s := range o.MLQ.Shares {
+		if s <= 0 {
+			return fmt.Errorf("%w: multilevel queue shares must be positive, got %d", ErrInvalidArgs, s)
+		}
+	}
+	if o.CPUs < 1 {
+		return fmt.Errorf("%w: need at least one CPU, got %d", ErrInvalidArgs, o.CPUs)
+	}
+	if o.RunQueue != RunQueueGlobal && o.RunQueue != RunQueuePerCPU {
+		return fmt.Errorf("%w: run queue must be %q or %q, got %q", ErrInvalidArgs, RunQueueGlobal, RunQueuePerCPU, o.RunQueue)
+	}
+	if o.Prediction.Alpha < 0 || o.Prediction.Alpha > 1 {
+		return fmt.Errorf("%w: prediction alpha must be between 0 and 1, got %v", ErrInvalidArgs, o.Prediction.Alpha)
+	}
+	if o.Balance.Interval > 0 && o.RunQueue != RunQueuePerCPU {
+		return fmt.Errorf("%w: load balancing needs %q run queues", ErrInvalidArgs, RunQueuePerCPU)
+	}
+
+	return nil
+}
+
+// ValidateProcesses reports processes that cannot be run with these options:
+// those whose affinity allows none of the CPUs, and those restricted to some
+// of several CPUs when every CPU shares one run queue.
+func (o Options) ValidateProcesses(processes []Process) error {
+	all := int64(1)<<o.CPUs - 1
+	for _, p := range processes {
+		if p.Affinity == 0 {
+			continue
+		}
+		if p.Affinity&all == 0 {
+			return fmt.Errorf("%w: affinity %d of process %d allows none of the %d CPUs", ErrInvalidArgs, p.Affinity, p.ProcessID, o.CPUs)
+		}
+		if p.Affinity&all != all && o.RunQueue != RunQueuePerCPU {
+			return fmt.Errorf("%w: affinity of process %d needs %q run queues", ErrInvalidArgs, p.ProcessID, RunQueuePerCPU)
+		}
+	}
+
+	return nil
+}
+
+// Result is the outcome of running a Scheduler: the Gantt chart, the timings of
+// every process in input order, and the averages over all of them.
+type Result struct {
+	Gantt         []TimeSlice
+	Processes     []ProcessStats
+	Columns       []Column
+	AveWait       float64
+	AveTurnaround float64
+	AveResponse   float64
+	Throughput    float64
+	// Utilization is the fraction of the time from the first arrival to the
+	// last completion that the CPU spent running processes, so that idle
+	// time and time spent switching count against it.
+	Utilization float64
+	// ContextSwitches counts the dispatcher slices in the Gantt chart.
+	ContextSwitches int
+	// Idle is the total length of the idle slices in the Gantt chart.
+	Idle int64
+	// DeadlineMisses counts the processes that completed after their
+	// deadline, and MaxLateness is the largest lateness of any process with
+	// a deadline.
+	DeadlineMisses int
+	MaxLateness    int64
+	// ShowLevels marks results whose Gantt slices carry a queue level.
+	ShowLevels bool
+	// Classes breaks the timings down by process class, for schedulers that
+	// treat classes differently.
+	Classes []GroupStats
+	// Groups breaks the timings down by the group processes run for, for
+	// schedulers that share the CPU between groups.
+	Groups []GroupStats
+	// Log explains each scheduling decision, for schedulers run with the
+	// Verbose option.
+	Log []string
+	// CPUs breaks the time down by processor when there is more than one.
+	CPUs []CPUStats
+	// Devices holds the chart and utilization of each I/O device that was
+	// used, by name. Their slices are not in Gantt.
+	Devices []DeviceStats
+}
+
+// DeviceStats is how one I/O device spent the time from the first arrival to
+// the last completion.
+type DeviceStats struct {
+	Name        string
+	Gantt       []TimeSlice
+	Busy        int64
+	Requests    int
+	Utilization float64
+}
+
+// CPUStats is how one processor spent the time from the first arrival to the
+// last completion.
+type CPUStats struct {
+	CPU         int
+	Busy        int64
+	Switching   int64
+	Migrating   int64
+	Idle        int64
+	Utilization float64
+}
+
+// GroupStats summarises the processes that share some attribute, such as a class.
+type GroupStats struct {
+	Name          string
+	Processes     int
+	CPUTime       int64
+	AveWait       float64
+	AveTurnaround float64
+	AveResponse   float64
+	// Entitlement is the fraction of the CPU the group should get while
+	// every group has work, and Share the fraction it got. Both are 0
+	// unless the scheduler shares the CPU between groups.
+	Entitlement float64
+	Share       float64
+}
+
+// ProcessStats holds the timings of one process in a schedule.
+type ProcessStats struct {
+	Process
+	// Start is the first time the process was given the CPU.
+	Start int64
+	// Wait is the total time the process spent ready but not running, which
+	// leaves out time spent on I/O.
+	Wait int64
+	// IOWait is the time the process spent queued for busy I/O devices.
+	IOWait int64
+	// Turnaround is the time from arrival to completion.
+	Turnaround int64
+	// Response is the time from arrival to first running.
+	Response int64
+	// Completion is the time the process finished.
+	Completion int64
+	// Lateness is how long after its deadline the process completed, and is
+	// negative if it finished early. It is 0 for processes without a deadline.
+	Lateness int64
+	// Migrations counts the times the load balancer moved the process to
+	// another CPU.
+	Migrations int
+	// PriorityHistory records each change to the process's effective
+	// priority, and is empty unless the scheduler ages priorities.
+	PriorityHistory []PriorityChange
+}
+
+// Column is an extra, scheduler-specific column in the schedule table, with
+// one value per entry in Result.Processes.
+type Column struct {
+	Header string
+	Values []string
+	Footer string
+}
+
+// Factory builds a Scheduler from a set of options.
+type Factory func(Options) Scheduler
+
+// Registration describes a scheduler available in the registry.
+type Registration struct {
+	Name  string
+	Title string
+	New   Factory
+}
+
+var (
+	ErrUnknownScheduler = errors.New("unknown scheduler")
+
+	registry = make(map[string]Registration)
+)
+
+// Register makes a scheduler available by name. It panics if the name is
+// empty or already taken, as that is a programming error.
+func Register(name, title string, f Factory) {
+	if name == "" || f == nil {
+		panic("scheduler: Register called with empty name or nil factory")
+	}
+	if _, dup := registry[name]; dup {
+		panic("scheduler: Register called twice for " + name)
+	}
+	registry[name] = Registration{Name: name, Title: title, New: f}
+}
+
+// Lookup returns the registration for the named scheduler.
+func Lookup(name string) (Registration, error) {
+	reg, ok := registry[name]
+	if !ok {
+		return Registration{}, fmt.Errorf("%w: %q", ErrUnknownScheduler, name)
+	}
+
+	return reg, nil
+}
+
+// Registered returns every registered scheduler, sorted by name.
+func Registered() []Registration {
+	regs := make([]Registration, 0, len(registry))
+	for _, reg := range registry {
+		regs = append(regs, reg)
+	}
+	sort.Slice(regs, func(i, j int) bool { return regs[i].Name < regs[j].Name })
+
+	return regs
+}
+
+// funcScheduler adapts a plain scheduling function to the Scheduler interface.
+type funcScheduler struct {
+	name string
+	opts Options
+	run  func(Options, []Process) Result
+}
+
+func (s funcScheduler) Name() string     { return s.name }
+func (s funcScheduler) Options() Options { return s.opts }
+
+func (s funcScheduler) Run(processes []Process) Result {
+	r := s.run(s.opts, processes)
+	if s.opts.CPUs > 1 {
+		addCPUStats(&r, s.opts.CPUs)
+	}
+	if s.opts.Balance.Interval > 0 {
+		migrations := make([]string, len(r.Processes))
+		for i, p := range r.Processes {
+			migrations[i] = fmt.Sprint(p.Migrations)
+		}
+		r.Columns = append(r.Columns, Column{Header: "Migrations", Values: migrations})
+	}
+
+	return r
+}
+
+// groupStats summarises r.Processes grouped by key, in order of first appearance.
+func groupStats(r Result, key func(ProcessStats) string) []GroupStats {
+	var groups []GroupStats
+	index := make(map[string]int)
+	for _, p := range r.Processes {
+		name := key(p)
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, GroupStats{Name: name})
+		}
+		g := &groups[i]
+		g.Processes++
+		g.CPUTime += p.BurstDuration
+		g.AveWait += float64(p.Wait)
+		g.AveTurnaround += float64(p.Turnaround)
+		g.AveResponse += float64(p.Response)
+	}
+	for i := range groups {
+		count := float64(groups[i].Processes)
+		groups[i].AveWait /= count
+		groups[i].AveTurnaround /= count
+		groups[i].AveResponse /= count
+	}
+
+	return groups
+}
+
+// listSchedulers writes the name and title of every registered scheduler, with
+// the titles lined up after the longest name.
+func listSchedulers(w io.Writer) {
+	regs := Registered()
+	width := 0
+	for _, reg := range regs {
+		if len(reg.Name) > width {
+			width = len(reg.Name)
+		}
+	}
+	for _, reg := range regs {
+		_, _ = fmt.Fprintf(w, "%-*s %s\n", width, reg.Name, reg.Title)
+	}
+}
+
+// newResult gathers the timings of finished tasks into a Result.
+func newResult(tasks []*Task, gantt []TimeSlice) Result {
+	var (
+		totalWait       float64
+		totalTurnaround float64
+		totalResponse   float64
+		lastCompletion  float64
+		firstArrival    float64
+		busy            float64
+		switches        int
+		idle            int64
+		stats           = make([]ProcessStats, len(tasks))
+	)
+	gantt, devices := splitDevices(gantt)
+	for i, t := range tasks {
+		stats[i] = ProcessStats{
+			Process:         t.Process,
+			Start:           t.Start,
+			IOWait:          t.IOWait,
+			Turnaround:      t.Completion - t.ArrivalTime,
+			Response:        t.Start - t.ArrivalTime,
+			Completion:      t.Completion,
+			Migrations:      t.Migrations,
+			PriorityHistory: t.PriorityHistory,
+		}
+		stats[i].Wait = stats[i].Turnaround - t.BurstDuration - t.ioTime() - t.IOWait
+		if t.Deadline > 0 {
+			stats[i].Lateness = t.Completion - t.Deadline
+		}
+		totalWait += float64(stats[i].Wait)
+		totalTurnaround += float64(stats[i].Turnaround)
+		totalResponse += float64(stats[i].Response)
+		if c := float64(t.Completion); c > lastCompletion {
+			lastCompletion = c
+		}
+		if a := float64(t.ArrivalTime); i == 0 || a < firstArrival {
+			firstArrival = a
+		}
+	}
+	for _, s := range gantt {
+		switch s.PID {
+		case DispatcherPID:
+			switches++
+			continue
+		case IdlePID:
+			idle += s.Stop - s.Start
+			continue
+		case MigrationPID:
+			continue
+		}
+		busy += float64(s.Stop - s.Start)
+	}
+
+	count := float64(len(tasks))
+	utilization := 1.0
+	span := lastCompletion - firstArrival
+	if span > 0 {
+		utilization = busy / span
+	}
+	for i := range devices {
+		devices[i].Utilization = 1
+		if span > 0 {
+			devices[i].Utilization = float64(devices[i].Busy) / span
+		}
+	}
+	r := Result{
+		Gantt:           gantt,
+		Processes:       stats,
+		AveWait:         totalWait / count,
+		AveTurnaround:   totalTurnaround / count,
+		AveResponse:     totalResponse / count,
+		Throughput:      count / lastCompletion,
+		Utilization:     utilization,
+		ContextSwitches: switches,
+		Idle:            idle,
+		Devices:         devices,
+	}
+	addDeadlineColumns(&r)
+	addIOColumn(&r)
+
+	return r
+}
+
+// splitDevices takes the slices of I/O devices out of gantt and returns them
+// per device, sorted by name.
+func splitDevices(gantt []TimeSlice) ([]TimeSlice, []DeviceStats) {
+	var (
+		cpus    []TimeSlice
+		devices []DeviceStats
+		index   = make(map[string]int)
+	)
+	for _, s := range gantt {
+		if s.Device == "" {
+			cpus = append(cpus, s)
+			continue
+		}
+		i, ok := index[s.Device]
+		if !ok {
+			i = len(devices)
+			index[s.Device] = i
+			devices = append(devices, DeviceStats{Name: s.Device})
+		}
+		d := &devices[i]
+		d.Gantt = append(d.Gantt, s)
+		if s.PID != IdlePID {
+			d.Busy += s.Stop - s.Start
+			d.Requests++
+		}
+	}
+	if len(devices) == 0 {
+		return gantt, nil
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Name < devices[j].Name })
+
+	return cpus, devices
+}
+
+// addCPUStats breaks r down by processor, and measures utilization against
+// every CPU rather than just one.
+func addCPUStats(r *Result, cpus int) {
+	var first, last int64
+	for i, p := range r.Processes {
+		if i == 0 || p.ArrivalTime < first {
+			first = p.ArrivalTime
+		}
+		if p.Completion > last {
+			last = p.Completion
+		}
+	}
+	span := last - first
+
+	r.CPUs = make([]CPUStats, cpus)
+	var busy int64
+	for i := range r.CPUs {
+		r.CPUs[i].CPU = i
+	}
+	for _, s := range r.Gantt {
+		switch s.PID {
+		case DispatcherPID:
+			r.CPUs[s.CPU].Switching += s.Stop - s.Start
+			continue
+		case MigrationPID:
+			r.CPUs[s.CPU].Migrating += s.Stop - s.Start
+			continue
+		case IdlePID:
+			continue
+		}
+		r.CPUs[s.CPU].Busy += s.Stop - s.Start
+		busy += s.Stop - s.Start
+	}
+	for i := range r.CPUs {
+		c := &r.CPUs[i]
+		c.Idle = span - c.Busy - c.Switching - c.Migrating
+		c.Utilization = 1
+		if span > 0 {
+			c.Utilization = float64(c.Busy) / float64(span)
+		}
+	}
+	r.Utilization = 1
+	if span > 0 {
+		r.Utilization = float64(busy) / float64(span*int64(cpus))
+	}
+}
+
+// addDeadlineColumns reports how each process did against its deadline, when
+// any process has one.
+func addDeadlineColumns(r *Result) {
+	deadlines := make([]string, len(r.Processes))
+	lateness := make([]string, len(r.Processes))
+	met := make([]string, len(r.Processes))
+	found := false
+	for i, p := range r.Processes {
+		if p.Deadline <= 0 {
+			deadlines[i], lateness[i], met[i] = "-", "-", "-"
+			continue
+		}
+		if !found || p.Lateness > r.MaxLateness {
+			r.MaxLateness = p.Lateness
+		}
+		found = true
+		deadlines[i] = fmt.Sprint(p.Deadline)
+		lateness[i] = fmt.Sprint(p.Lateness)
+		met[i] = "yes"
+		if p.Lateness > 0 {
+			met[i] = "no"
+			r.DeadlineMisses++
+		}
+	}
+	if !found {
+		return
+	}
+	r.Columns = append(r.Columns,
+		Column{Header: "Deadline", Values: deadlines},
+		Column{Header: "Lateness", Values: lateness, Footer: fmt.Sprintf("Max\n%d", r.MaxLateness)},
+		Column{Header: "Met?", Values: met, Footer: fmt.Sprintf("Misses\n%d", r.DeadlineMisses)},
+	)
+}
+
+// addIOColumn reports how long each process spent on I/O, when any process
+// did, and how long it queued for devices, when any were modelled.
+func addIOColumn(r *Result) {
+	values := make([]string, len(r.Processes))
+	found := false
+	for i, p := range r.Processes {
+		values[i] = fmt.Sprint(p.ioTime())
+		found = found || len(p.Bursts) > 0
+	}
+	if !found {
+		return
+	}
+	r.Columns = append(r.Columns, Column{Header: "I/O", Values: values})
+	if len(r.Devices) == 0 {
+		return
+	}
+	waits := make([]string, len(r.Processes))
+	for i, p := range r.Processes {
+		waits[i] = fmt.Sprint(p.IOWait)
+	}
+	r.Columns = append(r.Columns, Column{Header: "I/O wait", Values: waits})
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+	}{
+		{name: "fcfs", want: "fcfs"},
+		{name: "sjf", want: "sjf"},
+		{name: "priority", want: "priority"},
+		{name: "rr", want: "rr"},
+		{name: "nope", wantErr: ErrUnknownScheduler},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			reg, err := Lookup(tt.name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			s := reg.New(DefaultOptions())
+			if got := s.Name(); got != tt.want {
+				t.Errorf("Name() = %v, want %v", got, tt.want)
+			}
+			if got := s.Options(); !reflect.DeepEqual(got, DefaultOptions()) {
+				t.Errorf("Options() = %v, want %v", got, DefaultOptions())
+			}
+		})
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	tests := []struct {
+		name      string
+		scheduler string
+		want      []ProcessStats
+	}{
+		{
+			name:      "fcfs",
+			scheduler: "fcfs",
+			want: []ProcessStats{
+				{Process: processes[0], Start: 0, Wait: 0, Turnaround: 5, Response: 0, Completion: 5},
+				{Process: processes[1], Start: 5, Wait: 2, Turnaround: 11, Response: 2, Completion: 14},
+				{Process: processes[2], Start: 14, Wait: 8, Turnaround: 14, Response: 8, Completion: 20},
+			},
+		},
+		{
+			name:      "round-robin",
+			scheduler: "rr",
+			want: []ProcessStats{
+				{Process: processes[0], Start: 0, Wait: 2, Turnaround: 7, Response: 0, Completion: 7},
+				{Process: processes[1], Start: 3, Wait: 8, Turnaround: 17, Response: 0, Completion: 20},
+				{Process: processes[2], Start: 7, Wait: 6, Turnaround: 12, Response: 1, Completion: 18},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			reg, err := Lookup(tt.scheduler)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := reg.New(DefaultOptions()).Run(processes)
+			if !reflect.DeepEqual(got.Processes, tt.want) {
+				t.Errorf("Run().Processes = %v, want %v", got.Processes, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortestJobPreemption(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 8},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 4},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 9},
+		{ProcessID: 4, ArrivalTime: 3, BurstDuration: 5},
+	}
+	tests := []struct {
+		name      string
+		scheduler string
+		want      []TimeSlice
+	}{
+		{
+			name:      "sjf runs each job to completion",
+			scheduler: "sjf",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 8},
+				{PID: 2, Start: 8, Stop: 12},
+				{PID: 4, Start: 12, Stop: 17},
+				{PID: 3, Start: 17, Stop: 26},
+			},
+		},
+		{
+			name:      "srtf preempts on remaining time",
+			scheduler: "srtf",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 5},
+				{PID: 4, Start: 5, Stop: 10},
+				{PID: 1, Start: 10, Stop: 17},
+				{PID: 3, Start: 17, Stop: 26},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			reg, err := Lookup(tt.scheduler)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := reg.New(DefaultOptions()).Run(processes).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run().Gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrioritySchedulers(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 3},
+		{ProcessID: 2, ArrivalTime: 2, BurstDuration: 3, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 2, Priority: 2},
+	}
+	tests := []struct {
+		name      string
+		scheduler string
+		highFirst bool
+		want      []TimeSlice
+	}{
+		{
+			name:      "non-preemptive",
+			scheduler: "priority",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 8},
+				{PID: 3, Start: 8, Stop: 10},
+			},
+		},
+		{
+			name:      "preemptive",
+			scheduler: "preemptive-priority",
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 5},
+				{PID: 3, Start: 5, Stop: 7},
+				{PID: 1, Start: 7, Stop: 10},
+			},
+		},
+		{
+			name:      "preemptive with high numbers first",
+			scheduler: "preemptive-priority",
+			highFirst: true,
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 3, Start: 5, Stop: 7},
+				{PID: 2, Start: 7, Stop: 10},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			reg, err := Lookup(tt.scheduler)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := DefaultOptions()
+			opts.HighPriorityFirst = tt.highFirst
+			if got := reg.New(opts).Run(processes).Gantt; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run().Gantt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCPUStats(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: 4, ArrivalTime: 2, BurstDuration: 1},
+	}
+	opts := DefaultOptions()
+	opts.CPUs = 2
+	opts.RunQueue = RunQueuePerCPU
+	reg, err := Lookup("fcfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reg.New(opts).Run(processes)
+
+	want := []CPUStats{
+		{CPU: 0, Busy: 6, Idle: 0, Utilization: 1},
+		{CPU: 1, Busy: 4, Idle: 2, Utilization: 4.0 / 6.0},
+	}
+	if !reflect.DeepEqual(got.CPUs, want) {
+		t.Errorf("CPUs = %v, want %v", got.CPUs, want)
+	}
+	if want := 10.0 / 12.0; got.Utilization != want {
+		t.Errorf("Utilization = %v, want %v", got.Utilization, want)
+	}
+}
+
+func TestIdleGaps(t *testing.T) {
+	t.Parallel()
+	// Process 2 arrives long after process 1 has finished.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 6, BurstDuration: 2, Priority: 1},
+	}
+	for _, reg := range Registered() {
+		reg := reg
+		t.Run(reg.Name, func(t *testing.T) {
+			t.Parallel()
+			got := reg.New(DefaultOptions()).Run(processes)
+			var idle []TimeSlice
+			for _, s := range got.Gantt {
+				if s.PID == IdlePID {
+					idle = append(idle, s)
+				}
+			}
+			if want := []TimeSlice{{PID: IdlePID, Start: 3, Stop: 6}}; !reflect.DeepEqual(idle, want) {
+				t.Errorf("idle slices = %v, want %v", idle, want)
+			}
+			for _, p := range got.Processes {
+				if p.Wait != 0 {
+					t.Errorf("process %d wait = %d, want 0", p.ProcessID, p.Wait)
+				}
+			}
+			if got.Idle != 3 || got.Utilization != 5.0/8.0 {
+				t.Errorf("Idle = %d, Utilization = %v, want 3, %v", got.Idle, got.Utilization, 5.0/8.0)
+			}
+		})
+	}
+}
+
+func TestValidateProcesses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		affinity int64
+		runQueue string
+		wantErr  error
+	}{
+		{name: "any CPU", affinity: 0, runQueue: RunQueueGlobal},
+		{name: "every CPU", affinity: 3, runQueue: RunQueueGlobal},
+		{name: "some CPUs per-cpu", affinity: 2, runQueue: RunQueuePerCPU},
+		{name: "some CPUs global", affinity: 2, runQueue: RunQueueGlobal, wantErr: ErrInvalidArgs},
+		{name: "no CPU", affinity: 4, runQueue: RunQueuePerCPU, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultOptions()
+			opts.CPUs = 2
+			opts.RunQueue = tt.runQueue
+			processes := []Process{{ProcessID: 1, BurstDuration: 5, Affinity: tt.affinity}}
+			if err := opts.ValidateProcesses(processes); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateProcesses() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		modify  func(*Options)
+		wantErr error
+	}{
+		{name: "defaults", modify: func(*Options) {}},
+		{name: "zero quantum", modify: func(o *Options) { o.Quantum = 0 }, wantErr: ErrInvalidArgs},
+		{name: "negative quantum", modify: func(o *Options) { o.Quantum = -1 }, wantErr: ErrInvalidArgs},
+		{name: "negative context switch", modify: func(o *Options) { o.ContextSwitch = -2 }, wantErr: ErrInvalidArgs},
+		{name: "mlq shares", modify: func(o *Options) { o.MLQ.Shares = []int64{3, 2, 1} }},
+		{name: "zero mlq shares", modify: func(o *Options) { o.MLQ.Shares = []int64{0, 0, 0} }, wantErr: ErrInvalidArgs},
+		{name: "negative mlq share", modify: func(o *Options) { o.MLQ.Shares = []int64{2, -1, 1} }, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultOptions()
+			tt.modify(&opts)
+			if err := opts.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,710 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+//region Simulation engine
+
+// Task is the simulator's view of a process while it is being scheduled.
+type Task struct {
+	Process
+	// Index is the position of the process in the input slice.
+	Index int
+	// Remaining is the CPU time the task still needs in its current burst.
+	Remaining int64
+	// Burst is the index in Bursts of the burst the task is on.
+	Burst int
+	// Waiting is set while the task is blocked on I/O.
+	Waiting bool
+	// IOWait is the time the task spent queued for busy I/O devices.
+	IOWait int64
+	// Start is the time the task was first dispatched, or -1 before that.
+	Start int64
+	// Completion is the time the task finished.
+	Completion int64
+	// ReadyAt is the last time the task entered the ready queue.
+	ReadyAt int64
+	// Effective is the priority the task is scheduled by. It starts out as
+	// Priority and only differs when a policy ages waiting tasks.
+	Effective int64
+	// PriorityHistory records every change to Effective.
+	PriorityHistory []PriorityChange
+	// Level is the queue the task belongs to under a multilevel policy.
+	Level int
+	// Migrations counts the times the load balancer moved the task.
+	Migrations int
+}
+
+// burst is the length of the CPU burst t is on.
+func (t *Task) burst() int64 {
+	if len(t.Bursts) == 0 {
+		return t.BurstDuration
+	}
+	return t.Bursts[t.Burst].Duration
+}
+
+// IdlePID is the PID of Gantt slices a CPU spent with nothing to run,
+// DispatcherPID of those spent switching between processes, and MigrationPID
+// of those spent warming up a CPU a task was moved to.
+const (
+	IdlePID       int64 = -1
+	DispatcherPID int64 = -2
+	MigrationPID  int64 = -3
+)
+
+// PriorityChange is a point at which a task's effective priority changed.
+type PriorityChange struct {
+	At       int64
+	Priority int64
+}
+
+// Policy is the decision-making half of a scheduler. The engine owns the clock
+// and the event queue; the policy owns the ready queue and decides which task
+// runs next and for how long.
+type Policy interface {
+	// Ready adds a task to the ready queue.
+	Ready(now int64, t *Task)
+	// Next removes and returns the task to dispatch, or nil if none is ready.
+	Next(now int64) *Task
+	// Slice returns how long t may run before it is preempted, or 0 to let it
+	// run to completion.
+	Slice(now int64, t *Task) int64
+	// Preempt reports whether the running task should give up the CPU now
+	// that another task has become ready.
+	Preempt(now int64, running *Task) bool
+}
+
+// completer is implemented by policies that need to know when a task leaves
+// the CPU without going back to the ready queue, because it finished or
+// blocked on I/O. A blocked task is made ready again once its I/O is done.
+type completer interface {
+	Complete(now int64, t *Task)
+}
+
+// timedPolicy is implemented by policies that need to act at times other than
+// arrivals and completions, such as when aging waiting tasks.
+type timedPolicy interface {
+	Policy
+	// Wake returns the next time the policy wants Timer called, or -1 if it
+	// has nothing pending.
+	Wake(now int64) int64
+	// Timer is called at the time last returned by Wake.
+	Timer(now int64)
+}
+
+// ranker is implemented by preemptive policies that can rank running tasks, so
+// that a task which becomes ready on a shared run queue preempts whichever CPU
+// is running the task the policy would run last.
+type ranker interface {
+	// Ahead reports whether a would run before b.
+	Ahead(a, b *Task) bool
+}
+
+// stealer is implemented by policies that let the load balancer take a waiting
+// task away to another CPU's run queue.
+type stealer interface {
+	// Steal removes and returns the waiting task for which allowed is true
+	// that would otherwise run last, or nil if there is none.
+	Steal(now int64, allowed func(*Task) bool) *Task
+}
+
+// eventKind orders events that happen at the same instant: a completion frees
+// the CPU and a finished context switch starts its task before anything else
+// is looked at, arrivals and then tasks done with I/O join the ready queue
+// before an expired task is put back behind them, policy timers and then the
+// load balancer see the queues once they have settled, and dispatch runs last.
+type eventKind int
+
+const (
+	eventCompletion eventKind = iota
+	eventSwitched
+	eventArrival
+	eventIO
+	eventPreempt
+	eventTimer
+	eventBalance
+	eventDispatch
+)
+
+type event struct {
+	at   int64
+	kind eventKind
+	task *Task
+	cpu  *processor // the CPU a switch, completion, preemption or dispatch is for
+	rq   *runQueue  // the run queue a timer is for
+	dev  *device    // the device an I/O burst was served by, if it contended for one
+	gen  int        // dispatch generation, used to drop events for a task that was preempted early
+	seq  int
+}
+
+type eventQueue []event
+
+func (q eventQueue) Len() int { return len(q) }
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	if q[i].kind != q[j].kind {
+		return q[i].kind < q[j].kind
+	}
+	return q[i].seq < q[j].seq
+}
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x any)   { *q = append(*q, x.(event)) }
+func (q *eventQueue) Pop() any {
+	old := *q
+	ev := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return ev
+}
+
+// runQueue is a policy and the CPUs that take their work from it: every CPU
+// with a global run queue, or just one with a run queue per CPU.
+type runQueue struct {
+	policy  Policy
+	cpus    []*processor
+	load    int   // tasks queued on or running from this run queue
+	timerAt int64 // time of the queued policy timer, or -1
+}
+
+// processor is one CPU and the task it is running.
+type processor struct {
+	id int
+	rq *runQueue
+
+	running    *Task
+	since      int64 // last time running.Remaining was brought up to date
+	sliceStart int64
+	sliceLevel int
+	switching  bool  // the running task is still being switched in
+	last       *Task // the task that most recently held the CPU
+	lastSlice  int   // index in the Gantt chart of this CPU's latest slice, or -1
+	gen        int
+	pending    bool  // a dispatch event is already queued
+	idleSince  int64 // when the CPU last ran out of work, or -1 while it has some
+}
+
+type engine struct {
+	switchCost int64
+	balance    BalanceOptions
+	tasks      []*Task
+	queues     []*runQueue
+	events     eventQueue
+	seq        int
+	now        int64
+	done       int
+	blocked    int            // tasks waiting on I/O
+	cold       map[*Task]bool // tasks moved to a CPU they have not run on yet
+	devices    map[string]*device
+
+	gantt []TimeSlice
+}
+
+// simulate runs the processes through the policies newPolicy makes and returns
+// the finished tasks, in input order, along with the Gantt chart. The CPUs
+// option sets how many processors there are; they share one policy unless the
+// RunQueue option asks for one per CPU, in which case the Balance option can
+// move waiting tasks between them. Switching a CPU from one task to another
+// costs the ContextSwitch option's worth of time, which shows up in the chart
+// as a DispatcherPID slice, and time a CPU has nothing to run between the
+// first arrival and the last completion as an IdlePID slice. A process with
+// I/O bursts blocks between its CPU bursts. I/O bursts for the devices in the
+// Devices option queue for them, and the time each device spends serving them
+// is in the chart too, as slices with the device's name.
+func simulate(processes []Process, newPolicy func() Policy, opts Options) ([]*Task, []TimeSlice) {
+	e := &engine{switchCost: opts.ContextSwitch, balance: opts.Balance, tasks: make([]*Task, len(processes)), cold: make(map[*Task]bool), devices: make(map[string]*device)}
+	for _, d := range opts.Devices {
+		e.devices[d.Name] = newDevice(d, opts)
+	}
+	cpus := opts.CPUs
+	if cpus < 1 {
+		cpus = 1
+	}
+	var first int64
+	for i := range processes {
+		if i == 0 || processes[i].ArrivalTime < first {
+			first = processes[i].ArrivalTime
+		}
+	}
+	for i := 0; i < cpus; i++ {
+		if i == 0 || opts.RunQueue == RunQueuePerCPU {
+			e.queues = append(e.queues, &runQueue{policy: newPolicy(), timerAt: -1})
+		}
+		rq := e.queues[len(e.queues)-1]
+		rq.cpus = append(rq.cpus, &processor{id: i, rq: rq, lastSlice: -1, idleSince: first})
+	}
+	for i := range processes {
+		e.tasks[i] = &Task{
+			Process:   processes[i],
+			Index:     i,
+			Start:     -1,
+			Effective: processes[i].Priority,
+		}
+		e.push(event{at: processes[i].ArrivalTime, kind: eventArrival, task: e.tasks[i]})
+	}
+	if e.balance.Interval > 0 && len(e.queues) > 1 {
+		e.push(event{at: e.balance.Interval, kind: eventBalance})
+	}
+
+	for e.events.Len() > 0 {
+		ev := heap.Pop(&e.events).(event)
+		e.now = ev.at
+		c := ev.cpu
+		switch ev.kind {
+		case eventSwitched:
+			if ev.task == c.running && ev.gen == c.gen {
+				e.start(c)
+				e.check(c.rq)
+			}
+		case eventArrival:
+			e.enter(ev.task)
+		case eventIO:
+			if ev.dev != nil {
+				e.release(ev.dev)
+			}
+			ev.task.Waiting = false
+			e.blocked--
+			e.next(ev.task)
+		case eventCompletion:
+			if ev.task == c.running && ev.gen == c.gen {
+				e.complete(c)
+			}
+		case eventPreempt:
+			if ev.task == c.running && ev.gen == c.gen {
+				e.preempt(c)
+			}
+		case eventTimer:
+			if ev.at == ev.rq.timerAt {
+				e.timer(ev.rq)
+			}
+		case eventBalance:
+			e.rebalance()
+		case eventDispatch:
+			e.dispatch(c)
+		}
+		for _, rq := range e.queues {
+			e.arm(rq)
+		}
+	}
+
+	var last int64
+	for _, t := range e.tasks {
+		if t.Completion > last {
+			last = t.Completion
+		}
+	}
+	for _, rq := range e.queues {
+		for _, c := range rq.cpus {
+			if c.idleSince >= 0 && last > c.idleSince {
+				e.gantt = append(e.gantt, TimeSlice{PID: IdlePID, Start: c.idleSince, Stop: last, CPU: c.id})
+			}
+		}
+	}
+
+	return e.tasks, e.gantt
+}
+
+func (e *engine) push(ev event) {
+	ev.seq = e.seq
+	e.seq++
+	heap.Push(&e.events, ev)
+}
+
+func (e *engine) schedule(c *processor) {
+	if !c.pending {
+		c.pending = true
+		e.push(event{at: e.now, kind: eventDispatch, cpu: c})
+	}
+}
+
+// advance charges the task running on c for the time since it was last updated.
+func (e *engine) advance(c *processor) {
+	if c.running != nil {
+		c.running.Remaining -= e.now - c.since
+		c.since = e.now
+	}
+}
+
+// place picks the run queue for a task that has become ready: the least loaded one
+// its affinity allows, or the first of those that tie. Options.ValidateProcesses
+// rejects tasks whose affinity allows none of the CPUs.
+func (e *engine) place(t *Task) *runQueue {
+	var rq *runQueue
+	for _, q := range e.queues {
+		if e.allowed(t, q) && (rq == nil || q.load < rq.load) {
+			rq = q
+		}
+	}
+	if rq == nil {
+		panic(fmt.Sprintf("scheduler: affinity %d of process %d allows none of the CPUs", t.Affinity, t.ProcessID))
+	}
+	rq.load++
+
+	return rq
+}
+
+// allowed reports whether t's affinity lets it run from rq. A global run queue
+// serves every CPU, so any task with an affinity may run from it.
+func (e *engine) allowed(t *Task, rq *runQueue) bool {
+	if len(e.queues) == 1 {
+		return true
+	}
+	return t.allowedOn(rq.cpus[0].id)
+}
+
+// rebalance moves waiting tasks from the busiest run queue to the least busy
+// one until no run queue has two tasks more than another, or none of the
+// waiting tasks may move. It then arranges to run again while work remains.
+func (e *engine) rebalance() {
+	for {
+		busiest, idlest := e.queues[0], e.queues[0]
+		for _, q := range e.queues[1:] {
+			if q.load > busiest.load {
+				busiest = q
+			}
+			if q.load < idlest.load {
+				idlest = q
+			}
+		}
+		if busiest.load-idlest.load < 2 {
+			break
+		}
+		s, ok := busiest.policy.(stealer)
+		if !ok {
+			break
+		}
+		t := s.Steal(e.now, func(t *Task) bool { return e.allowed(t, idlest) })
+		if t == nil {
+			break
+		}
+		busiest.load--
+		idlest.load++
+		t.Migrations++
+		e.cold[t] = true
+		idlest.policy.Ready(e.now, t)
+		e.check(idlest)
+	}
+	if e.done < len(e.tasks) {
+		e.push(event{at: e.now + e.balance.Interval, kind: eventBalance})
+	}
+}
+
+// enter puts t on its current burst: a CPU burst joins a run queue, and an
+// I/O burst blocks the task until the I/O is done.
+func (e *engine) enter(t *Task) {
+	if len(t.Bursts) > 0 && t.Bursts[t.Burst].IO {
+		t.Waiting = true
+		e.blocked++
+		if !e.request(t) {
+			e.push(event{at: e.now + t.Bursts[t.Burst].Duration, kind: eventIO, task: t})
+		}
+		return
+	}
+	t.Remaining = t.burst()
+	rq := e.place(t)
+	t.ReadyAt = e.now
+	rq.policy.Ready(e.now, t)
+	e.check(rq)
+}
+
+// check hands newly ready work on rq to its idle CPUs, or else preempts the
+// CPU whose task the policy no longer prefers: the one running the task ranked
+// last, if the policy is a ranker, or otherwise the first. A task that is still
+// being switched in is checked once it starts.
+func (e *engine) check(rq *runQueue) {
+	idle := false
+	for _, c := range rq.cpus {
+		if c.running == nil {
+			e.schedule(c)
+			idle = true
+		}
+	}
+	if idle {
+		return
+	}
+	r, ranked := rq.policy.(ranker)
+	var last *processor
+	for _, c := range rq.cpus {
+		if c.switching {
+			continue
+		}
+		e.advance(c)
+		if c.running.Remaining <= 0 {
+			continue
+		}
+		if !ranked {
+			if rq.policy.Preempt(e.now, c.running) {
+				e.preempt(c)
+				return
+			}
+			continue
+		}
+		if last == nil || r.Ahead(last.running, c.running) {
+			last = c
+		}
+	}
+	if last != nil && rq.policy.Preempt(e.now, last.running) {
+		e.preempt(last)
+	}
+}
+
+// arm queues a timer event for the next wake-up of rq's policy, if it has one.
+func (e *engine) arm(rq *runQueue) {
+	tp, ok := rq.policy.(timedPolicy)
+	if !ok {
+		return
+	}
+	at := tp.Wake(e.now)
+	if at < 0 || at == rq.timerAt {
+		return
+	}
+	if at < e.now {
+		at = e.now
+	}
+	rq.timerAt = at
+	e.push(event{at: at, kind: eventTimer, rq: rq})
+}
+
+func (e *engine) timer(rq *runQueue) {
+	rq.timerAt = -1
+	rq.policy.(timedPolicy).Timer(e.now)
+	e.check(rq)
+}
+
+func (e *engine) dispatch(c *processor) {
+	c.pending = false
+	if c.running != nil {
+		return
+	}
+	t := c.rq.policy.Next(e.now)
+	if t == nil {
+		if c.idleSince < 0 {
+			c.idleSince = e.now
+		}
+		return
+	}
+	if c.idleSince >= 0 && e.now > c.idleSince {
+		c.lastSlice = len(e.gantt)
+		e.gantt = append(e.gantt, TimeSlice{PID: IdlePID, Start: c.idleSince, Stop: e.now, CPU: c.id})
+	}
+	c.idleSince = -1
+	c.running = t
+	c.gen++
+	at := e.now
+	if e.switchCost > 0 && c.last != nil && c.last != t {
+		c.lastSlice = len(e.gantt)
+		e.gantt = append(e.gantt, TimeSlice{PID: DispatcherPID, Start: at, Stop: at + e.switchCost, CPU: c.id})
+		at += e.switchCost
+	}
+	if e.cold[t] {
+		delete(e.cold, t)
+		if e.balance.MigrationCost > 0 {
+			c.lastSlice = len(e.gantt)
+			e.gantt = append(e.gantt, TimeSlice{PID: MigrationPID, Start: at, Stop: at + e.balance.MigrationCost, CPU: c.id})
+			at += e.balance.MigrationCost
+		}
+	}
+	if at > e.now {
+		c.switching = true
+		e.push(event{at: at, kind: eventSwitched, task: t, cpu: c, gen: c.gen})
+		return
+	}
+	e.start(c)
+}
+
+// start gives c to its running task once any context switch is over.
+func (e *engine) start(c *processor) {
+	t := c.running
+	c.switching = false
+	c.last = t
+	if t.Start < 0 {
+		t.Start = e.now
+	}
+	c.since, c.sliceStart, c.sliceLevel = e.now, e.now, t.Level
+
+	if s := c.rq.policy.Slice(e.now, t); s > 0 && s < t.Remaining {
+		e.push(event{at: e.now + s, kind: eventPreempt, task: t, cpu: c, gen: c.gen})
+		return
+	}
+	e.push(event{at: e.now + t.Remaining, kind: eventCompletion, task: t, cpu: c, gen: c.gen})
+}
+
+// stop takes the running task off c and records the slice it ran for.
+func (e *engine) stop(c *processor) *Task {
+	e.advance(c)
+	t := c.running
+	c.running = nil
+	if e.now > c.sliceStart {
+		if i := c.lastSlice; i >= 0 && e.gantt[i].PID == t.ProcessID && e.gantt[i].Job == t.Job && e.gantt[i].Stop == c.sliceStart && e.gantt[i].Level == c.sliceLevel {
+			e.gantt[i].Stop = e.now
+		} else {
+			c.lastSlice = len(e.gantt)
+			e.gantt = append(e.gantt, TimeSlice{PID: t.ProcessID, Start: c.sliceStart, Stop: e.now, Level: c.sliceLevel, Job: t.Job, CPU: c.id})
+		}
+	}
+	e.schedule(c)
+
+	return t
+}
+
+// complete takes a task off c at the end of a CPU burst.
+func (e *engine) complete(c *processor) {
+	t := e.stop(c)
+	c.rq.load--
+	if p, ok := c.rq.policy.(completer); ok {
+		p.Complete(e.now, t)
+	}
+	e.next(t)
+}
+
+// next moves t on from the burst it has just finished, and finishes the task
+// after its last one.
+func (e *engine) next(t *Task) {
+	t.Burst++
+	if t.Burst >= len(t.Bursts) {
+		t.Completion = e.now
+		e.done++
+		return
+	}
+	e.enter(t)
+}
+
+func (e *engine) preempt(c *processor) {
+	t := e.stop(c)
+	t.ReadyAt = e.now
+	c.rq.policy.Ready(e.now, t)
+}
+
+//endregion
+
+//region Ready queues
+
+// readyQueue is a priority queue of tasks. Tasks that compare equal under less
+// (or every task, when less is nil) come out in the order they went in.
+type readyQueue struct {
+	less  func(a, b *Task) bool
+	items []queued
+	seq   int
+}
+
+type queued struct {
+	task *Task
+	seq  int
+}
+
+func (q *readyQueue) Len() int { return len(q.items) }
+func (q *readyQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if q.less != nil {
+		if q.less(a.task, b.task) {
+			return true
+		}
+		if q.less(b.task, a.task) {
+			return false
+		}
+	}
+	return a.seq < b.seq
+}
+func (q *readyQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *readyQueue) Push(x any)    { q.items = append(q.items, x.(queued)) }
+func (q *readyQueue) Pop() any {
+	it := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return it
+}
+
+func (q *readyQueue) add(t *Task) {
+	heap.Push(q, queued{task: t, seq: q.seq})
+	q.seq++
+}
+
+// fix restores the queue order after the keys of queued tasks have changed.
+func (q *readyQueue) fix() { heap.Init(q) }
+
+func (q *readyQueue) peek() *Task {
+	if len(q.items) == 0 {
+		return nil
+	}
+	return q.items[0].task
+}
+
+// steal removes and returns the task for which allowed is true that would come
+// out of the queue last, or nil if there is none.
+func (q *readyQueue) steal(allowed func(*Task) bool) *Task {
+	last := -1
+	for i := range q.items {
+		if allowed(q.items[i].task) && (last < 0 || q.Less(last, i)) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+	return heap.Remove(q, last).(queued).task
+}
+
+func (q *readyQueue) next() *Task {
+	if len(q.items) == 0 {
+		return nil
+	}
+	return heap.Pop(q).(queued).task
+}
+
+// orderedPolicy runs tasks in the order given by a ready queue. A non-zero
+// quantum makes it time-sliced, and a preemptive policy gives up the CPU as
+// soon as a task that sorts ahead of the running one becomes ready.
+type orderedPolicy struct {
+	queue      readyQueue
+	quantum    int64
+	preemptive bool
+}
+
+func newOrderedPolicy(less func(a, b *Task) bool, quantum int64, preemptive bool) *orderedPolicy {
+	return &orderedPolicy{queue: readyQueue{less: less}, quantum: quantum, preemptive: preemptive}
+}
+
+// indexOf returns the index of t in tasks, or -1.
+func indexOf(tasks []*Task, t *Task) int {
+	for i, u := range tasks {
+		if u == t {
+			return i
+		}
+	}
+	return -1
+}
+
+// collect returns a function that makes policies with newPolicy, for simulate,
+// and keeps each one in ps so that schedulers can read their state afterwards.
+func collect[P Policy](ps *[]P, newPolicy func() P) func() Policy {
+	return func() Policy {
+		p := newPolicy()
+		*ps = append(*ps, p)
+		return p
+	}
+}
+
+// ordered returns a function that makes orderedPolicies, for simulate.
+func ordered(less func(a, b *Task) bool, quantum int64, preemptive bool) func() Policy {
+	return func() Policy { return newOrderedPolicy(less, quantum, preemptive) }
+}
+
+func (p *orderedPolicy) Ready(_ int64, t *Task)   { p.queue.add(t) }
+func (p *orderedPolicy) Next(int64) *Task         { return p.queue.next() }
+func (p *orderedPolicy) Slice(int64, *Task) int64 { return p.quantum }
+func (p *orderedPolicy) Steal(_ int64, allowed func(*Task) bool) *Task {
+	return p.queue.steal(allowed)
+}
+func (p *orderedPolicy) Ahead(a, b *Task) bool {
+	return p.queue.less != nil && p.queue.less(a, b)
+}
+func (p *orderedPolicy) Preempt(_ int64, r *Task) bool {
+	if !p.preemptive || p.queue.less == nil {
+		return false
+	}
+	next := p.queue.peek()
+	return next != nil && p.queue.less(next, r)
+}
+
+//endregion
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_simulate(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		processes []Process
+		policy    func() Policy
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantGantt      []TimeSlice
+		wantCompletion []int64
+	}{
+		{
+			name: "fcfs past old horizon with large PIDs",
+			args: args{
+				processes: []Process{
+					{ProcessID: 250, ArrivalTime: 0, BurstDuration: 90},
+					{ProcessID: 1000, ArrivalTime: 40, BurstDuration: 60},
+				},
+				policy: func() Policy { return newOrderedPolicy(nil, 0, false) },
+			},
+			wantGantt: []TimeSlice{
+				{PID: 250, Start: 0, Stop: 90},
+				{PID: 1000, Start: 90, Stop: 150},
+			},
+			wantCompletion: []int64{90, 150},
+		},
+		{
+			name: "round-robin requeues behind new arrivals",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3},
+					{ProcessID: 2, ArrivalTime: 2, BurstDuration: 2},
+				},
+				policy: func() Policy { return newOrderedPolicy(nil, 2, false) },
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 4},
+				{PID: 1, Start: 4, Stop: 5},
+			},
+			wantCompletion: []int64{5, 4},
+		},
+		{
+			name: "preemptive policy splits the running slice",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10},
+					{ProcessID: 2, ArrivalTime: 4, BurstDuration: 2},
+				},
+				policy: func() Policy { return newOrderedPolicy(shorterBurst, 0, true) },
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 2, Start: 4, Stop: 6},
+				{PID: 1, Start: 6, Stop: 12},
+			},
+			wantCompletion: []int64{12, 6},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tasks, gantt := simulate(tt.args.processes, tt.args.policy, DefaultOptions())
+			if !reflect.DeepEqual(gantt, tt.wantGantt) {
+				t.Errorf("simulate() gantt = %v, want %v", gantt, tt.wantGantt)
+			}
+			for i, task := range tasks {
+				if task.Completion != tt.wantCompletion[i] {
+					t.Errorf("task %d completion = %v, want %v", task.ProcessID, task.Completion, tt.wantCompletion[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_simulateContextSwitch(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 5},
+	}
+	opts := DefaultOptions()
+	opts.ContextSwitch = 1
+	tasks, gantt := simulate(processes, ordered(nil, 4, false), opts)
+
+	// The first process needs less than a quantum, and the second keeps the
+	// CPU without a switch when it is the only one left.
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 3},
+		{PID: DispatcherPID, Start: 3, Stop: 4},
+		{PID: 2, Start: 4, Stop: 9},
+	}
+	if !reflect.DeepEqual(gantt, wantGantt) {
+		t.Errorf("simulate() gantt = %v, want %v", gantt, wantGantt)
+	}
+	if tasks[1].Start != 4 {
+		t.Errorf("second task start = %v, want 4", tasks[1].Start)
+	}
+
+	r := newResult(tasks, gantt)
+	if r.ContextSwitches != 1 {
+		t.Errorf("ContextSwitches = %v, want 1", r.ContextSwitches)
+	}
+	if want := 8.0 / 9.0; r.Utilization != want {
+		t.Errorf("Utilization = %v, want %v", r.Utilization, want)
+	}
+}
+
+func Test_simulateCPUs(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: 4, ArrivalTime: 2, BurstDuration: 1},
+	}
+	tests := []struct {
+		name      string
+		runQueue  string
+		wantGantt []TimeSlice
+	}{
+		{
+			name:     "global run queue",
+			runQueue: RunQueueGlobal,
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 3, CPU: 1},
+				{PID: 1, Start: 0, Stop: 4, CPU: 0},
+				{PID: 3, Start: 3, Stop: 5, CPU: 1},
+				{PID: 4, Start: 4, Stop: 5, CPU: 0},
+			},
+		},
+		{
+			// Process 3 joins CPU 0's queue while both CPUs have one
+			// process each, so it waits even though CPU 1 finishes first.
+			name:     "per-CPU run queues",
+			runQueue: RunQueuePerCPU,
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 3, CPU: 1},
+				{PID: 1, Start: 0, Stop: 4, CPU: 0},
+				{PID: 4, Start: 3, Stop: 4, CPU: 1},
+				{PID: 3, Start: 4, Stop: 6, CPU: 0},
+				{PID: IdlePID, Start: 4, Stop: 6, CPU: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultOptions()
+			opts.CPUs = 2
+			opts.RunQueue = tt.runQueue
+			if _, gantt := simulate(processes, ordered(nil, 0, false), opts); !reflect.DeepEqual(gantt, tt.wantGantt) {
+				t.Errorf("simulate() gantt = %v, want %v", gantt, tt.wantGantt)
+			}
+		})
+	}
+}
+
+func Test_simulateCPUsPreempt(t *testing.T) {
+	t.Parallel()
+	// Process 3 arrives while both CPUs are busy and should take the CPU
+	// from process 2, the least urgent, rather than from process 1.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 10, Priority: 9},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 10, Priority: 1},
+	}
+	want := []TimeSlice{
+		{PID: 2, Start: 0, Stop: 1, CPU: 1},
+		{PID: 1, Start: 0, Stop: 10, CPU: 0},
+		{PID: 3, Start: 1, Stop: 11, CPU: 1},
+		{PID: 2, Start: 10, Stop: 19, CPU: 0},
+		{PID: IdlePID, Start: 11, Stop: 19, CPU: 1},
+	}
+	opts := DefaultOptions()
+	opts.CPUs = 2
+	if _, gantt := simulate(processes, func() Policy { return newPriorityPolicy(opts, true) }, opts); !reflect.DeepEqual(gantt, want) {
+		t.Errorf("simulate() gantt = %v, want %v", gantt, want)
+	}
+}
+
+func Test_simulateBalance(t *testing.T) {
+	t.Parallel()
+	// The short processes all land on CPU 0, which runs dry while CPU 1
+	// still has two waiting. Process 6 is pinned to CPU 1, so the balancer
+	// moves process 4 instead.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 1},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: 3, ArrivalTime: 0, BurstDuration: 1},
+		{ProcessID: 4, ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: 5, ArrivalTime: 0, BurstDuration: 1},
+		{ProcessID: 6, ArrivalTime: 0, BurstDuration: 10, Affinity: 2},
+	}
+	opts := DefaultOptions()
+	opts.CPUs = 2
+	opts.RunQueue = RunQueuePerCPU
+	opts.Balance = BalanceOptions{Interval: 2, MigrationCost: 1}
+	tasks, gantt := simulate(processes, ordered(nil, 0, false), opts)
+
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1},
+		{PID: 3, Start: 1, Stop: 2},
+		{PID: 5, Start: 2, Stop: 3},
+		{PID: MigrationPID, Start: 3, Stop: 4},
+		{PID: 2, Start: 0, Stop: 10, CPU: 1},
+		{PID: 4, Start: 4, Stop: 14},
+		{PID: 6, Start: 10, Stop: 20, CPU: 1},
+		{PID: IdlePID, Start: 14, Stop: 20},
+	}
+	if !reflect.DeepEqual(gantt, wantGantt) {
+		t.Errorf("simulate() gantt = %v, want %v", gantt, wantGantt)
+	}
+	for _, task := range tasks {
+		want := 0
+		if task.ProcessID == 4 {
+			want = 1
+		}
+		if task.Migrations != want {
+			t.Errorf("process %d migrations = %d, want %d", task.ProcessID, task.Migrations, want)
+		}
+	}
+}
+
+func Test_simulateIO(t *testing.T) {
+	t.Parallel()
+	// Both processes are blocked from 5 to 7 and from 9 to 11, leaving the
+	// CPU idle.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Bursts: []Burst{{Duration: 3}, {IO: true, Duration: 4}, {Duration: 2}}},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3, Bursts: []Burst{{Duration: 2}, {IO: true, Duration: 6}, {Duration: 1}}},
+	}
+	tasks, gantt := simulate(processes, ordered(nil, 0, false), DefaultOptions())
+
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 3},
+		{PID: 2, Start: 3, Stop: 5},
+		{PID: IdlePID, Start: 5, Stop: 7},
+		{PID: 1, Start: 7, Stop: 9},
+		{PID: IdlePID, Start: 9, Stop: 11},
+		{PID: 2, Start: 11, Stop: 12},
+	}
+	if !reflect.DeepEqual(gantt, wantGantt) {
+		t.Errorf("simulate() gantt = %v, want %v", gantt, wantGantt)
+	}
+	for i, want := range []int64{9, 12} {
+		if tasks[i].Completion != want {
+			t.Errorf("task %d completion = %v, want %v", tasks[i].ProcessID, tasks[i].Completion, want)
+		}
+	}
+
+	r := newResult(tasks, gantt)
+	for i, want := range []int64{0, 2} {
+		if r.Processes[i].Wait != want {
+			t.Errorf("process %d wait = %v, want %v", r.Processes[i].ProcessID, r.Processes[i].Wait, want)
+		}
+	}
+	if r.Utilization != 8.0/12 {
+		t.Errorf("utilization = %v, want %v", r.Utilization, 8.0/12)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import "fmt"
+
+func init() {
+	Register("stride", "Stride", func(opts Options) Scheduler {
+		return funcScheduler{name: "stride", opts: opts, run: stride}
+	})
+}
+
+// strideOne is the stride of a process holding a single ticket.
+const strideOne = 10000
+
+// stride is the deterministic counterpart of lottery. Each process's stride is
+// inversely proportional to its tickets (its Priority, at least one), and every
+// quantum goes to the ready process with the lowest pass, which then advances
+// by its stride for each time unit it runs.
+func stride(opts Options, processes []Process) Result {
+	var policies []*stridePolicy
+	tasks, gantt := simulate(processes, collect(&policies, func() *stridePolicy { return newStridePolicy(opts) }), opts)
+	r := newResult(tasks, gantt)
+
+	tickets := make([]string, len(tasks))
+	strides := make([]string, len(tasks))
+	passes := make([]string, len(tasks))
+	for i, t := range tasks {
+		tickets[i] = fmt.Sprint(ticketsOf(t))
+		strides[i] = fmt.Sprint(strideOf(t))
+		for _, p := range policies {
+			if pass, ok := p.pass[t]; ok {
+				passes[i] = fmt.Sprint(pass)
+			}
+		}
+	}
+	r.Columns = append(r.Columns,
+		Column{Header: "Tickets", Values: tickets},
+		Column{Header: "Stride", Values: strides},
+		Column{Header: "Pass", Values: passes},
+	)
+
+	return r
+}
+
+type stridePolicy struct {
+	queue   readyQueue
+	quantum int64
+	pass    map[*Task]int64
+	global  int64          // pass of the most recently dispatched task
+	asleep  map[*Task]bool // tasks that left the CPU without being requeued
+}
+
+func newStridePolicy(opts Options) *stridePolicy {
+	p := &stridePolicy{quantum: opts.Quantum, pass: make(map[*Task]int64), asleep: make(map[*Task]bool)}
+	p.queue.less = func(a, b *Task) bool { return p.pass[a] < p.pass[b] }
+
+	return p
+}
+
+func strideOf(t *Task) int64 { return strideOne / ticketsOf(t) }
+
+// Ready queues t. A newcomer starts at the current global pass so that it
+// neither jumps ahead of nor falls behind the processes already running, and
+// a task back from I/O is brought up to it so that it cannot make up for the
+// time it was blocked.
+func (p *stridePolicy) Ready(_ int64, t *Task) {
+	pass, ok := p.pass[t]
+	if !ok || p.asleep[t] && pass < p.global {
+		p.pass[t] = p.global
+	}
+	delete(p.asleep, t)
+	p.queue.add(t)
+}
+
+func (p *stridePolicy) Complete(_ int64, t *Task) { p.asleep[t] = true }
+
+func (p *stridePolicy) Next(int64) *Task {
+	t := p.queue.next()
+	if t == nil {
+		return nil
+	}
+	p.global = p.pass[t]
+	run := t.Remaining
+	if p.quantum > 0 && p.quantum < run {
+		run = p.quantum
+	}
+	p.pass[t] += strideOf(t) * run
+
+	return t
+}
+
+// Steal gives up a waiting task to another CPU, where it starts again from
+// that CPU's global pass.
+func (p *stridePolicy) Steal(_ int64, allowed func(*Task) bool) *Task {
+	t := p.queue.steal(allowed)
+	delete(p.pass, t)
+	return t
+}
+
+func (p *stridePolicy) Slice(int64, *Task) int64  { return p.quantum }
+func (p *stridePolicy) Preempt(int64, *Task) bool { return false }
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStride(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 4, Priority: 2},
+		{ProcessID: 3, ArrivalTime: 0, BurstDuration: 2, Priority: 4},
+	}
+	got := stride(DefaultOptions(), processes)
+
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1},
+		{PID: 2, Start: 1, Stop: 2},
+		{PID: 3, Start: 2, Stop: 4},
+		{PID: 2, Start: 4, Stop: 5},
+		{PID: 1, Start: 5, Stop: 6},
+		{PID: 2, Start: 6, Stop: 8},
+		{PID: 1, Start: 8, Stop: 9},
+	}
+	if !reflect.DeepEqual(got.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", got.Gantt, wantGantt)
+	}
+	wantColumns := []Column{
+		{Header: "Tickets", Values: []string{"1", "2", "4"}},
+		{Header: "Stride", Values: []string{"10000", "5000", "2500"}},
+		{Header: "Pass", Values: []string{"30000", "20000", "5000"}},
+	}
+	if !reflect.DeepEqual(got.Columns, wantColumns) {
+		t.Errorf("Columns = %v, want %v", got.Columns, wantColumns)
+	}
+}